@@ -22,21 +22,41 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/echoboomer/paranoidaf/pkg/eval"
 	"github.com/echoboomer/paranoidaf/pkg/kubetools"
+	"github.com/echoboomer/paranoidaf/pkg/render"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+// Exit codes returned by the eval command
+// These stay clear of 1, used by log.Fatal, and 2, used by the Go runtime when it panics
+const (
+	// exitFindings is returned when a finding at or above the --fail-on threshold is produced
+	exitFindings = 10
+	// exitClusterError is returned when the cluster could not be talked to
+	exitClusterError = 11
+)
+
 // evalOptions holds configuration options to pass into the eval package
 type evalOptions struct {
+	failOn    string
 	namespace string
+	out       string
+	output    string
 }
 
 // evalOpts holds default and customizable values from the command line
-var evalOpts *evalOptions = &evalOptions{}
+var evalOpts *evalOptions = &evalOptions{
+	output: "text",
+}
 
 // evalCmd represents the eval command
 var evalCmd = &cobra.Command{
@@ -50,6 +70,28 @@ Node scaling. This is done by evaluating replica counts, PodDisruptionBudgets,
 and HorizontalPodAutoscalers. Helpful suggestions will be given to help improve
 resiliency.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		// Validate options before talking to the cluster
+		if !render.Supported(evalOpts.output) {
+			log.Fatalf("Unsupported output format %q, must be one of %v", evalOpts.output, render.Formats())
+		}
+		var threshold eval.Severity
+		if evalOpts.failOn != "" {
+			var err error
+			threshold, err = eval.ParseSeverity(evalOpts.failOn)
+			if err != nil {
+				log.Fatalf("Invalid --fail-on value: %s", err)
+			}
+		}
+
+		// Read rule configuration from the config file, if any
+		cfg := eval.DefaultConfig()
+		if err := viper.Unmarshal(cfg); err != nil {
+			log.Fatalf("Error reading config: %s", err)
+		}
+		if err := cfg.Validate(); err != nil {
+			log.Fatalf("Invalid config: %s", err)
+		}
+
 		// Initiate kubeconfig
 		config, clientset, _ := kubetools.CreateKubeConfig(false)
 		clientconfig, err := clientcmd.NewDefaultClientConfigLoadingRules().Load()
@@ -57,19 +99,59 @@ resiliency.`,
 			log.Fatalf("Error loading client config: %s", err)
 		}
 
+		currentContext, ok := clientconfig.Contexts[clientconfig.CurrentContext]
+		if !ok || currentContext == nil {
+			log.Printf("Error checking cluster: kubeconfig has no current context %q", clientconfig.CurrentContext)
+			os.Exit(exitClusterError)
+		}
+
 		// Format options
 		evalupgradeOpts := &eval.UGPrepOptions{
-			ClusterName: clientconfig.Contexts[clientconfig.CurrentContext].Cluster,
+			ClusterName: currentContext.Cluster,
 			Namespace:   evalOpts.namespace,
+			Config:      cfg,
 		}
 
 		// Start
-		eval.Check(config, clientset, evalupgradeOpts)
+		report, err := eval.Check(config, clientset, evalupgradeOpts)
+		if err != nil {
+			log.Printf("Error checking cluster: %s", err)
+			os.Exit(exitClusterError)
+		}
+		if err := writeReport(report); err != nil {
+			log.Fatalf("Error rendering report: %s", err)
+		}
+
+		// Fail if anything at or above the requested severity was found
+		if threshold != "" && report.Exceeds(threshold) {
+			os.Exit(exitFindings)
+		}
 	},
 }
 
+// writeReport renders the report to stdout unless a file was requested
+func writeReport(report *eval.Report) error {
+	if evalOpts.out == "" {
+		return render.Render(os.Stdout, evalOpts.output, report)
+	}
+	f, err := os.Create(filepath.Clean(evalOpts.out))
+	if err != nil {
+		return err
+	}
+	if err := render.Render(f, evalOpts.output, report); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
 func init() {
 	rootCmd.AddCommand(evalCmd)
 	// Flags for evalupgrade
+	evalCmd.Flags().StringVar(&evalOpts.failOn, "fail-on", evalOpts.failOn, "Exit with a non-zero code if any check fails with this severity or higher. One of: info|warning|error. By default, the exit code doesn't depend on results.")
+	evalCmd.Flags().Bool("hpa-label-fallback", false, "Match HorizontalPodAutoscalers using the workload's selector labels when none target it using spec.scaleTargetRef.")
+	cobra.CheckErr(viper.BindPFlag("hpaLabelFallback", evalCmd.Flags().Lookup("hpa-label-fallback")))
 	evalCmd.Flags().StringVar(&evalOpts.namespace, "namespace", evalOpts.namespace, "Namespace to check. By default, all Namespaces (except for ones filtered out) are checked.")
+	evalCmd.Flags().StringVar(&evalOpts.out, "out", evalOpts.out, "File to write the report to. By default, the report is written to stdout.")
+	evalCmd.Flags().StringVarP(&evalOpts.output, "output", "o", evalOpts.output, fmt.Sprintf("Output format. One of: %s.", strings.Join(render.Formats(), "|")))
 }
@@ -23,6 +23,7 @@ package common
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/common-nighthawk/go-figure"
 	"github.com/spf13/afero"
@@ -61,8 +62,8 @@ func FindInSlice(slice []string, element string) (int, bool) {
 }
 
 // PAFHeader returns a header for the app
+// It is written to stderr so that it doesn't interfere with machine-readable output
 func PAFHeader() {
 	myFigure := figure.NewFigure("paranoid af", "cosmic", true)
-	myFigure.Print()
-	fmt.Printf("\n")
+	fmt.Fprintf(os.Stderr, "%s\n", myFigure.String())
 }
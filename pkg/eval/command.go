@@ -23,143 +23,96 @@ package eval
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"strings"
 
-	"github.com/kyokomi/emoji/v2"
 	log "github.com/sirupsen/logrus"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
 
-// deploymentDescription returns information regarding each Deployment
-type deploymentDescription struct {
-	labels    string
-	name      string
-	namespace string
-	replicas  int32
-	selectors map[string]string
-}
-
 // hpaDescription returns information regarding a given HorizontalPodAutoscaler
+// currentReplicas, desiredReplicas and conditions come from its status
 type hpaDescription struct {
-	application string
-	max         int32
-	min         int32
-	name        string
-	namespace   string
+	application     string
+	behavior        *autoscalingv2.HorizontalPodAutoscalerBehavior
+	conditions      []autoscalingv2.HorizontalPodAutoscalerCondition
+	currentReplicas int32
+	desiredReplicas int32
+	max             int32
+	metrics         []autoscalingv2.MetricSpec
+	min             int32
+	name            string
+	namespace       string
 }
 
 // pdbDescription returns information regarding a given PodDisruptionBudget
+// availabilityConfig holds minAvailable and maxUnavailable as Pod counts, while
+// minAvailable and maxUnavailable hold the values as configured
 type pdbDescription struct {
 	application        string
 	availabilityConfig map[string]int32
+	maxUnavailable     *intstr.IntOrString
+	minAvailable       *intstr.IntOrString
 	name               string
 	namespace          string
 }
 
-// buildDeploymentDescription returns a struct with information regarding each Deployment
-// with information that can be used to calculate risk
-func buildDeploymentDescription(d appsv1.Deployment) *deploymentDescription {
-	// Make the labels a string
-	var container []string
-	var stringsAsLabels string
-	for k, v := range d.Spec.Selector.MatchLabels {
-		concatLabel := fmt.Sprintf("%s=%s", k, v)
-		container = append(container, concatLabel)
-		stringsAsLabels = strings.Join(container, ",")
-	}
-	return &deploymentDescription{
-		labels:    stringsAsLabels,
-		name:      d.Name,
-		namespace: d.Namespace,
-		replicas:  *d.Spec.Replicas,
-		selectors: d.Spec.Selector.MatchLabels,
+// checkWorkloads procsses a list of workloads and verifies their configurations as they
+// relate to high availability and resiliency
+func checkWorkloads(state *ClusterState, workloads []*Workload) []WorkloadReport {
+	var reports []WorkloadReport
+	for _, w := range workloads {
+		reports = append(reports, evaluateWorkload(w, state))
 	}
+	return reports
 }
 
-// checkDeployments procsses a list of Deployments and verifies their configurations as they
-// relate to high availability and resiliency
-func checkDeployments(clientset kubernetes.Interface, deployments []appsv1.Deployment) {
-	fmt.Println()
-	for _, d := range deployments {
-		// Build a struct for each Deployment
-		dep := buildDeploymentDescription(d)
-		_, err := emoji.Printf(":package: %s\n", dep.name)
-		if err != nil {
-			log.Fatal(err)
-		}
-		fmt.Printf("----------------------------------------------------------------------\n")
-		_, err = emoji.Printf(":information_source:	Current replicas: %v\n", dep.replicas)
-		if err != nil {
-			log.Fatal(err)
-		}
-		_, err = emoji.Printf(":information_source:	Matching resources using labels: %v\n", dep.labels)
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		// Check for HorizontalPodAutoscaler
-		hpa := returnHorizontalPodAutoscalers(clientset, dep.name, dep.namespace, dep.labels)
-		if hpa.name == "" {
-			_, err = emoji.Printf(":warning:	Could not find a HorizontalPodAutoscaler using labels %s. Double check the labels. The Deployment replica count is likely static. Read more here: https://kubernetes.io/docs/tasks/run-application/horizontal-pod-autoscale/\n", dep.labels)
-			if err != nil {
-				log.Fatal(err)
-			}
-			if dep.replicas != 0 {
-				if dep.replicas < 2 {
-					_, err = emoji.Printf(":point_right:	Suggestion - verify that the minimum replica count is not set for a single replica, enable a HorizontalPodAutoscaler, and set minReplicas to at least 2.\n")
-					if err != nil {
-						log.Fatal(err)
-					}
-					_, err = emoji.Printf(":point_right:	Suggestion - add and enable a PodDisruptionBudget with at least a maxUnavailable less than configured min replicas.\n")
-					if err != nil {
-						log.Fatal(err)
-					}
-				} else {
-					_, err = emoji.Printf(":white_check_mark:	Current replica count is at least 2. This helps keep this application up during events like rollouts and upgrades.\n")
-					if err != nil {
-						log.Fatal(err)
-					}
-				}
-			} else {
-				_, err = emoji.Printf(":warning:	Couldn't figure out spec.replicas.")
-				if err != nil {
-					log.Fatal(err)
-				}
-			}
-		} else {
-			_, err = emoji.Printf(":white_check_mark:	This app has a HorizontalPodAutoscaler with %v min replicas and %v max replicas.\n", hpa.min, hpa.max)
-			if err != nil {
-				log.Fatal(err)
-			}
+// evaluateWorkload carries out every enabled Rule against a workload
+func evaluateWorkload(w *Workload, s *ClusterState) WorkloadReport {
+	report := WorkloadReport{
+		Kind:      w.Kind,
+		Name:      w.Name,
+		Namespace: w.Namespace,
+		Replicas:  w.Replicas,
+		Labels:    w.Labels,
+		Selectors: w.Selectors,
+	}
+	if w.Scalable() {
+		report.HPA, _ = s.HPA(w)
+		report.PDB, _ = s.PDB(w)
+	}
+	for _, r := range Rules() {
+		if !s.config.enabled(r.ID()) {
+			continue
 		}
-
-		// Check for PodDisruptionBudget
-		pdb := returnPodDisruptionBudgets(clientset, dep.name, dep.namespace, dep.labels)
-		if pdb.name == "" {
-			_, err = emoji.Printf(":warning:	This app does not have a PodDisruptionBudget. This application could experience interruptions during rollouts, upgrades, etc. Read more here: https://kubernetes.io/docs/concepts/workloads/pods/disruptions/\n")
-			if err != nil {
-				log.Fatal(err)
-			}
-			_, err = emoji.Printf(":point_right:	Suggestion - enable a PodDisruptionBudget.\n")
-			if err != nil {
-				log.Fatal(err)
-			}
-		} else {
-			_, err = emoji.Printf(":white_check_mark:	This app has a PodDisruptionBudget configured with: %v\n", pdb.availabilityConfig)
-			if err != nil {
-				log.Fatal(err)
+		findings := r.Evaluate(w, s)
+		if severity := s.config.Rules[r.ID()].Severity; severity != "" {
+			for i := range findings {
+				findings[i].Severity = severity
 			}
 		}
-		fmt.Println()
+		report.Findings = append(report.Findings, findings...)
 	}
+	return report
 }
 
 // returnEligibleDeployments accepts a []string containing target Namespaces
 // and returns a []v1.Deployment with related Deployment spec
-func returnEligibleDeployments(clientset kubernetes.Interface, nsList []string) []appsv1.Deployment {
+func returnEligibleDeployments(clientset kubernetes.Interface, nsList []string) ([]appsv1.Deployment, error) {
 	// Restrict which Pods to return
 	// We should return all that we own - i.e. not kube-system, etc.
 	deploymentListOptions := metav1.ListOptions{
@@ -174,77 +127,519 @@ func returnEligibleDeployments(clientset kubernetes.Interface, nsList []string)
 	for _, ns := range nsList {
 		deployments, err := clientset.AppsV1().Deployments(ns).List(context.TODO(), deploymentListOptions)
 		if err != nil {
-			log.Errorf("Error: %s", err)
+			return nil, fmt.Errorf("error listing Deployments in Namespace %s: %w", ns, err)
 		}
 		deploymentList = append(deploymentList, deployments.Items...)
 	}
-	return deploymentList
+	return deploymentList, nil
+}
+
+// returnEligibleDaemonSets accepts a []string containing target Namespaces
+// and returns a []v1.DaemonSet with related DaemonSet spec
+// Namespaces where DaemonSets can't be listed are skipped
+func returnEligibleDaemonSets(clientset kubernetes.Interface, nsList []string) ([]appsv1.DaemonSet, error) {
+	daemonSetListOptions := metav1.ListOptions{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "DaemonSet",
+			APIVersion: "apps/v1",
+		},
+	}
+
+	// Add each v1.DaemonSet to the returned list
+	var daemonSetList []appsv1.DaemonSet
+	for _, ns := range nsList {
+		daemonSets, err := clientset.AppsV1().DaemonSets(ns).List(context.TODO(), daemonSetListOptions)
+		if errors.IsForbidden(err) {
+			log.Warnf("Not allowed to list DaemonSets in Namespace %s, skipping them: %s", ns, err)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error listing DaemonSets in Namespace %s: %w", ns, err)
+		}
+		daemonSetList = append(daemonSetList, daemonSets.Items...)
+	}
+	return daemonSetList, nil
+}
+
+// rolloutResource identifies Argo Rollouts for the dynamic client
+var rolloutResource = schema.GroupVersionResource{
+	Group:    "argoproj.io",
+	Version:  "v1alpha1",
+	Resource: "rollouts",
+}
+
+// rolloutsServed returns whether or not discovery reports that the cluster serves Argo Rollouts
+// If discovery fails for another reason, Rollouts are assumed to be served so that listing
+// them gets the final say
+func rolloutsServed(clientset kubernetes.Interface) bool {
+	resources, err := clientset.Discovery().ServerResourcesForGroupVersion(rolloutResource.GroupVersion().String())
+	if errors.IsNotFound(err) {
+		return false
+	}
+	if err != nil {
+		log.Debugf("Unable to discover %s: %s", rolloutResource.GroupVersion().String(), err)
+		return true
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == rolloutResource.Resource {
+			return true
+		}
+	}
+	return false
 }
 
-// returnHorizontalPodAutoscalers returns a list of HorizontalPodAutoscalers for a given
-// application
-func returnHorizontalPodAutoscalers(clientset kubernetes.Interface, application string, ns string, labelsAsString string) *hpaDescription {
-	hpas, err := clientset.AutoscalingV1().HorizontalPodAutoscalers(ns).List(context.TODO(), metav1.ListOptions{
+// returnEligibleRollouts accepts a []string containing target Namespaces
+// and returns the Argo Rollouts in them
+// Nothing is returned if Argo Rollouts isn't installed in the cluster, and Namespaces
+// where Rollouts can't be listed are skipped
+func returnEligibleRollouts(client dynamic.Interface, nsList []string) ([]unstructured.Unstructured, error) {
+	var rolloutList []unstructured.Unstructured
+	for _, ns := range nsList {
+		rollouts, err := client.Resource(rolloutResource).Namespace(ns).List(context.TODO(), metav1.ListOptions{})
+		if errors.IsNotFound(err) {
+			log.Debugf("Argo Rollouts doesn't appear to be installed, skipping Rollouts")
+			return nil, nil
+		}
+		if errors.IsForbidden(err) {
+			log.Warnf("Not allowed to list Rollouts in Namespace %s, skipping them: %s", ns, err)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error listing Rollouts in Namespace %s: %w", ns, err)
+		}
+		rolloutList = append(rolloutList, rollouts.Items...)
+	}
+	return rolloutList, nil
+}
+
+// returnEligibleStatefulSets accepts a []string containing target Namespaces
+// and returns a []v1.StatefulSet with related StatefulSet spec
+// Namespaces where StatefulSets can't be listed are skipped
+func returnEligibleStatefulSets(clientset kubernetes.Interface, nsList []string) ([]appsv1.StatefulSet, error) {
+	statefulSetListOptions := metav1.ListOptions{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "StatefulSet",
+			APIVersion: "apps/v1",
+		},
+	}
+
+	// Add each v1.StatefulSet to the returned list
+	var statefulSetList []appsv1.StatefulSet
+	for _, ns := range nsList {
+		statefulSets, err := clientset.AppsV1().StatefulSets(ns).List(context.TODO(), statefulSetListOptions)
+		if errors.IsForbidden(err) {
+			log.Warnf("Not allowed to list StatefulSets in Namespace %s, skipping them: %s", ns, err)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error listing StatefulSets in Namespace %s: %w", ns, err)
+		}
+		statefulSetList = append(statefulSetList, statefulSets.Items...)
+	}
+	return statefulSetList, nil
+}
+
+// hpaVersions lists the autoscaling API versions HorizontalPodAutoscalers can be read
+// through, most preferred first
+var hpaVersions = []string{"autoscaling/v2", "autoscaling/v2beta2", "autoscaling/v1"}
+
+// preferredHPAVersion returns the newest autoscaling API version served by the cluster
+// autoscaling/v1 is assumed if discovery doesn't report any of the others
+func preferredHPAVersion(clientset kubernetes.Interface) string {
+	for _, version := range hpaVersions[:len(hpaVersions)-1] {
+		resources, err := clientset.Discovery().ServerResourcesForGroupVersion(version)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				log.Debugf("Unable to discover %s: %s", version, err)
+			}
+			continue
+		}
+		for _, r := range resources.APIResources {
+			if r.Name == "horizontalpodautoscalers" {
+				return version
+			}
+		}
+	}
+	return "autoscaling/v1"
+}
+
+// listHorizontalPodAutoscalers lists the HorizontalPodAutoscalers in a Namespace through the
+// given autoscaling API version, converting them to autoscaling/v2
+func listHorizontalPodAutoscalers(clientset kubernetes.Interface, namespace, apiVersion string) ([]autoscalingv2.HorizontalPodAutoscaler, error) {
+	listOptions := metav1.ListOptions{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "HorizontalPodAutoscaler",
-			APIVersion: "autoscaling/v1",
+			APIVersion: apiVersion,
 		},
-		LabelSelector: labelsAsString,
-	})
+	}
+	switch apiVersion {
+	case "autoscaling/v2":
+		hpas, err := clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(context.TODO(), listOptions)
+		if err != nil {
+			return nil, err
+		}
+		return hpas.Items, nil
+	case "autoscaling/v2beta2":
+		hpas, err := clientset.AutoscalingV2beta2().HorizontalPodAutoscalers(namespace).List(context.TODO(), listOptions)
+		if err != nil {
+			return nil, err
+		}
+		// autoscaling/v2beta2 and autoscaling/v2 share the same schema
+		var converted []autoscalingv2.HorizontalPodAutoscaler
+		data, err := json.Marshal(hpas.Items)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &converted); err != nil {
+			return nil, err
+		}
+		return converted, nil
+	default:
+		hpas, err := clientset.AutoscalingV1().HorizontalPodAutoscalers(namespace).List(context.TODO(), listOptions)
+		if err != nil {
+			return nil, err
+		}
+		var converted []autoscalingv2.HorizontalPodAutoscaler
+		for _, hpa := range hpas.Items {
+			converted = append(converted, convertV1HPA(hpa))
+		}
+		return converted, nil
+	}
+}
+
+// hpaConditionsAnnotation is where autoscaling/v1 keeps status conditions, since its
+// status has no field for them
+const hpaConditionsAnnotation = "autoscaling.alpha.kubernetes.io/conditions"
+
+// convertV1HPA returns an autoscaling/v1 HorizontalPodAutoscaler as autoscaling/v2
+// targetCPUUtilizationPercentage becomes a cpu Resource metric
+func convertV1HPA(hpa autoscalingv1.HorizontalPodAutoscaler) autoscalingv2.HorizontalPodAutoscaler {
+	converted := autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: hpa.ObjectMeta,
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference(hpa.Spec.ScaleTargetRef),
+			MinReplicas:    hpa.Spec.MinReplicas,
+			MaxReplicas:    hpa.Spec.MaxReplicas,
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: hpa.Status.CurrentReplicas,
+			DesiredReplicas: hpa.Status.DesiredReplicas,
+		},
+	}
+	if hpa.Spec.TargetCPUUtilizationPercentage != nil {
+		converted.Spec.Metrics = []autoscalingv2.MetricSpec{cpuUtilizationMetric(*hpa.Spec.TargetCPUUtilizationPercentage)}
+	}
+	if conditions, ok := hpa.Annotations[hpaConditionsAnnotation]; ok {
+		if err := json.Unmarshal([]byte(conditions), &converted.Status.Conditions); err != nil {
+			log.Debugf("Unable to read conditions of HorizontalPodAutoscaler %s: %s", hpa.Name, err)
+		}
+	}
+	return converted
+}
+
+// cpuUtilizationMetric returns a Resource metric targeting an average cpu utilization
+func cpuUtilizationMetric(percentage int32) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: corev1.ResourceCPU,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: &percentage,
+			},
+		},
+	}
+}
+
+// returnHorizontalPodAutoscalers returns the HorizontalPodAutoscaler in hpas whose
+// spec.scaleTargetRef points at a given workload
+// If labelFallback is set and nothing targets the workload, a HorizontalPodAutoscaler
+// sharing the workload's selector labels is returned instead
+func returnHorizontalPodAutoscalers(hpas []autoscalingv2.HorizontalPodAutoscaler, w *Workload, labelFallback bool) *hpaDescription {
+	for _, hpa := range hpas {
+		if targetsWorkload(hpa.Spec.ScaleTargetRef, w) {
+			return buildHPADescription(w.Name, hpa)
+		}
+	}
+
+	if !labelFallback || w.Labels == "" {
+		return &hpaDescription{}
+	}
+	selector, err := labels.Parse(w.Labels)
 	if err != nil {
 		log.Errorf("Error: %s", err)
+		return &hpaDescription{}
 	}
-
-	if len(hpas.Items) > 0 {
-		hpa := hpas.Items[0]
-		return &hpaDescription{
-			application: application,
-			max:         hpa.Spec.MaxReplicas,
-			min:         *hpa.Spec.MinReplicas,
-			name:        hpa.Name,
-			namespace:   hpa.Namespace,
+	for _, hpa := range hpas {
+		if selector.Matches(labels.Set(hpa.Labels)) {
+			return buildHPADescription(w.Name, hpa)
 		}
 	}
 	return &hpaDescription{}
 }
 
-// returnPodDisruptionBudgets returns a list of PodDisruptionBudgets for a given
-// application
-func returnPodDisruptionBudgets(clientset kubernetes.Interface, application string, ns string, labelsAsString string) *pdbDescription {
-	pdbs, err := clientset.PolicyV1().PodDisruptionBudgets(ns).List(context.TODO(), metav1.ListOptions{
+// buildHPADescription returns a struct with information regarding a HorizontalPodAutoscaler
+func buildHPADescription(application string, hpa autoscalingv2.HorizontalPodAutoscaler) *hpaDescription {
+	// minReplicas defaults to 1 when it isn't set
+	var minReplicas int32 = 1
+	if hpa.Spec.MinReplicas != nil {
+		minReplicas = *hpa.Spec.MinReplicas
+	}
+	// Without any metrics the controller targets 80% average cpu utilization
+	metrics := hpa.Spec.Metrics
+	if len(metrics) == 0 {
+		metrics = []autoscalingv2.MetricSpec{cpuUtilizationMetric(80)}
+	}
+	return &hpaDescription{
+		application:     application,
+		behavior:        hpa.Spec.Behavior,
+		conditions:      hpa.Status.Conditions,
+		currentReplicas: hpa.Status.CurrentReplicas,
+		desiredReplicas: hpa.Status.DesiredReplicas,
+		max:             hpa.Spec.MaxReplicas,
+		metrics:         metrics,
+		min:             minReplicas,
+		name:            hpa.Name,
+		namespace:       hpa.Namespace,
+	}
+}
+
+// describeMetric returns a HorizontalPodAutoscaler metric in the form used by reports
+func describeMetric(m autoscalingv2.MetricSpec) HPAMetric {
+	metric := HPAMetric{Type: string(m.Type)}
+	var target autoscalingv2.MetricTarget
+	switch {
+	case m.Resource != nil:
+		metric.Name = string(m.Resource.Name)
+		target = m.Resource.Target
+	case m.ContainerResource != nil:
+		metric.Name = string(m.ContainerResource.Name)
+		metric.Container = m.ContainerResource.Container
+		target = m.ContainerResource.Target
+	case m.Pods != nil:
+		metric.Name = m.Pods.Metric.Name
+		target = m.Pods.Target
+	case m.Object != nil:
+		metric.Name = fmt.Sprintf("%s on %s %s", m.Object.Metric.Name, m.Object.DescribedObject.Kind, m.Object.DescribedObject.Name)
+		target = m.Object.Target
+	case m.External != nil:
+		metric.Name = m.External.Metric.Name
+		target = m.External.Target
+	}
+	metric.TargetType = string(target.Type)
+	switch {
+	case target.AverageUtilization != nil:
+		metric.Target = fmt.Sprintf("%v%%", *target.AverageUtilization)
+	case target.AverageValue != nil:
+		metric.Target = target.AverageValue.String()
+	case target.Value != nil:
+		metric.Target = target.Value.String()
+	}
+	return metric
+}
+
+// kindGroups maps each kind of workload to its API group
+var kindGroups = map[string]string{
+	KindDaemonSet:   appsv1.GroupName,
+	KindDeployment:  appsv1.GroupName,
+	KindRollout:     rolloutResource.Group,
+	KindStatefulSet: appsv1.GroupName,
+}
+
+// targetsWorkload returns whether or not a HorizontalPodAutoscaler's scaleTargetRef
+// points at a given workload
+func targetsWorkload(ref autoscalingv2.CrossVersionObjectReference, w *Workload) bool {
+	if ref.Kind != w.Kind || ref.Name != w.Name {
+		return false
+	}
+	if ref.APIVersion == "" {
+		return true
+	}
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return false
+	}
+	return gv.Group == kindGroups[w.Kind]
+}
+
+// listPodDisruptionBudgets lists the PodDisruptionBudgets in a Namespace
+func listPodDisruptionBudgets(clientset kubernetes.Interface, namespace string) ([]policyv1.PodDisruptionBudget, error) {
+	pdbs, err := clientset.PolicyV1().PodDisruptionBudgets(namespace).List(context.TODO(), metav1.ListOptions{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "PodDisruptionBudget",
 			APIVersion: "policy/v1",
 		},
-		LabelSelector: labelsAsString,
 	})
 	if err != nil {
-		log.Errorf("Error: %s", err)
+		return nil, err
 	}
+	return pdbs.Items, nil
+}
 
-	if len(pdbs.Items) > 0 {
-		pdb := pdbs.Items[0]
-		var avcfg map[string]int32
-		if pdb.Spec.MaxUnavailable != nil && pdb.Spec.MinAvailable == nil {
-			avcfg = map[string]int32{
-				"maxUnavailable": pdb.Spec.MaxUnavailable.IntVal,
-			}
-		} else if pdb.Spec.MinAvailable != nil && pdb.Spec.MaxUnavailable == nil {
-			avcfg = map[string]int32{
-				"minAvailable": pdb.Spec.MinAvailable.IntVal,
+// returnPodDisruptionBudgets returns every PodDisruptionBudget in pdbs whose spec.selector
+// matches the labels of a given workload's Pods
+func returnPodDisruptionBudgets(pdbs []policyv1.PodDisruptionBudget, w *Workload) []*pdbDescription {
+	var matched []*pdbDescription
+	podLabels := labels.Set(w.PodLabels())
+	for _, pdb := range pdbs {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			log.Errorf("Error parsing selector of PodDisruptionBudget %s/%s: %s", pdb.Namespace, pdb.Name, err)
+			continue
+		}
+		if !selector.Matches(podLabels) {
+			continue
+		}
+
+		// Resolve the configured values to Pod counts, which matters for percentages
+		avcfg := map[string]int32{}
+		if pdb.Spec.MaxUnavailable != nil {
+			v, err := resolvePDBValue(pdb.Spec.MaxUnavailable, w.Replicas)
+			if err != nil {
+				log.Errorf("Error reading maxUnavailable of PodDisruptionBudget %s/%s: %s", pdb.Namespace, pdb.Name, err)
+			} else {
+				avcfg["maxUnavailable"] = v
 			}
-		} else {
-			avcfg = map[string]int32{
-				"maxUnavailable": pdb.Spec.MaxUnavailable.IntVal,
-				"minAvailable":   pdb.Spec.MinAvailable.IntVal,
+		}
+		if pdb.Spec.MinAvailable != nil {
+			v, err := resolvePDBValue(pdb.Spec.MinAvailable, w.Replicas)
+			if err != nil {
+				log.Errorf("Error reading minAvailable of PodDisruptionBudget %s/%s: %s", pdb.Namespace, pdb.Name, err)
+			} else {
+				avcfg["minAvailable"] = v
 			}
 		}
-		return &pdbDescription{
-			application:        application,
+		matched = append(matched, &pdbDescription{
+			application:        w.Name,
 			availabilityConfig: avcfg,
+			maxUnavailable:     pdb.Spec.MaxUnavailable,
+			minAvailable:       pdb.Spec.MinAvailable,
 			name:               pdb.Name,
 			namespace:          pdb.Namespace,
+		})
+	}
+	return matched
+}
+
+// resolvePDBValue returns the number of Pods a PodDisruptionBudget's minAvailable or
+// maxUnavailable works out to for a given number of expected Pods
+// Percentages are rounded up, the same as the disruption controller does
+func resolvePDBValue(v *intstr.IntOrString, expected int32) (int32, error) {
+	scaled, err := intstr.GetScaledValueFromIntOrPercent(v, int(expected), true)
+	if err != nil {
+		return 0, err
+	}
+	return int32(scaled), nil
+}
+
+// listPods lists the Pods in a Namespace
+func listPods(clientset kubernetes.Interface, namespace string) ([]corev1.Pod, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Pod",
+			APIVersion: "v1",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// listReplicaSets lists the ReplicaSets in a Namespace
+func listReplicaSets(clientset kubernetes.Interface, namespace string) ([]appsv1.ReplicaSet, error) {
+	replicaSets, err := clientset.AppsV1().ReplicaSets(namespace).List(context.TODO(), metav1.ListOptions{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ReplicaSet",
+			APIVersion: "apps/v1",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return replicaSets.Items, nil
+}
+
+// returnReadyPods returns the running and ready Pods in pods selected by a given workload's selector
+// Where the workload's object is known, only Pods it controls are returned so that other
+// workloads sharing its labels aren't counted
+// replicaSets is only used for Deployments and Rollouts, which control their Pods through them
+func returnReadyPods(pods []corev1.Pod, replicaSets []appsv1.ReplicaSet, w *Workload) ([]corev1.Pod, error) {
+	// A nil selector selects nothing
+	if w.Selector == nil {
+		return nil, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(w.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("error reading spec.selector of %s %s/%s: %w", w.Kind, w.Namespace, w.Name, err)
+	}
+	owners := returnPodOwners(replicaSets, w)
+
+	var ready []corev1.Pod
+	for _, pod := range pods {
+		if !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if owners != nil {
+			owner := metav1.GetControllerOf(&pod)
+			if owner == nil || !owners[owner.UID] {
+				continue
+			}
+		}
+		for _, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+				ready = append(ready, pod)
+				break
+			}
 		}
 	}
-	return &pdbDescription{}
+	return ready, nil
+}
+
+// ownsPodsThroughReplicaSets returns whether or not a workload controls its Pods through ReplicaSets
+func ownsPodsThroughReplicaSets(w *Workload) bool {
+	return w.Kind == KindDeployment || w.Kind == KindRollout
+}
+
+// returnPodOwners returns the UIDs of the objects that control a given workload's Pods
+// Deployments and Rollouts control their Pods through the ReplicaSets in replicaSets
+// nil is returned if the workload's object isn't known
+func returnPodOwners(replicaSets []appsv1.ReplicaSet, w *Workload) map[types.UID]bool {
+	object, err := meta.Accessor(w.Object)
+	if err != nil || object.GetUID() == "" {
+		return nil
+	}
+	owners := map[types.UID]bool{}
+	if !ownsPodsThroughReplicaSets(w) {
+		owners[object.GetUID()] = true
+		return owners
+	}
+	for i := range replicaSets {
+		if owner := metav1.GetControllerOf(&replicaSets[i]); owner != nil && owner.UID == object.GetUID() {
+			owners[replicaSets[i].UID] = true
+		}
+	}
+	return owners
+}
+
+// returnNodeLabels returns the labels of every Node in the cluster keyed by Node name
+// Listing Nodes needs cluster-scoped permissions
+func returnNodeLabels(clientset kubernetes.Interface) (map[string]map[string]string, error) {
+	nodes, err := clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Node",
+			APIVersion: "v1",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	nodeLabels := map[string]map[string]string{}
+	for _, node := range nodes.Items {
+		nodeLabels[node.Name] = node.Labels
+	}
+	return nodeLabels, nil
 }
@@ -22,18 +22,121 @@ THE SOFTWARE.
 package eval
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
+func Test_checkWorkloads(t *testing.T) {
+	var replicas int32 = 1
+	var minReplicas int32 = 2
+
+	type args struct {
+		clientset   kubernetes.Interface
+		deployments []appsv1.Deployment
+	}
+	tests := []struct {
+		name string
+		args args
+		want map[string]bool
+	}{
+		{
+			name: "A single replica Deployment without an HPA or PDB should fail every check",
+			args: args{
+				clientset: fake.NewSimpleClientset(),
+				deployments: []appsv1.Deployment{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+						Spec: appsv1.DeploymentSpec{
+							Replicas: &replicas,
+							Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+						},
+					},
+				},
+			},
+			want: map[string]bool{
+				RuleHPAConfigured: false,
+				RuleMinReplicas:   false,
+				RulePDBConfigured: false,
+			},
+		},
+		{
+			name: "A Deployment with a matching HPA should pass the HPA check and skip the replica check",
+			args: args{
+				clientset: fake.NewSimpleClientset(
+					&autoscalingv1.HorizontalPodAutoscaler{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "foo",
+							Namespace: "default",
+						},
+						Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+							ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{
+								APIVersion: "apps/v1",
+								Kind:       "Deployment",
+								Name:       "foo",
+							},
+							MinReplicas: &minReplicas,
+							MaxReplicas: 5,
+						},
+					},
+				),
+				deployments: []appsv1.Deployment{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+						Spec: appsv1.DeploymentSpec{
+							Replicas: &replicas,
+							Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+						},
+					},
+				},
+			},
+			want: map[string]bool{
+				RuleDeploymentStrategy: true,
+				RuleHPAConfigured:      true,
+				RuleHPAMetrics:         true,
+				RulePDBConfigured:      false,
+				RuleTopologySpread:     false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var workloads []*Workload
+			for _, d := range tt.args.deployments {
+				workloads = append(workloads, buildDeploymentWorkload(d))
+			}
+			reports := checkWorkloads(newClusterState(tt.args.clientset, DefaultConfig()), workloads)
+			if len(reports) != 1 {
+				t.Fatalf("checkWorkloads() returned %v reports, want 1", len(reports))
+			}
+			got := map[string]bool{}
+			for _, f := range reports[0].Findings {
+				got[f.RuleID] = f.Passed
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkWorkloads() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_returnEligibleDeployments(t *testing.T) {
 	var replicas int32 = 1
 
@@ -42,9 +145,10 @@ func Test_returnEligibleDeployments(t *testing.T) {
 		nsList    []string
 	}
 	tests := []struct {
-		name string
-		args args
-		want []appsv1.Deployment
+		name    string
+		args    args
+		want    []appsv1.Deployment
+		wantErr bool
 	}{
 		{
 			name: "Matched Deployment objects should be returned",
@@ -85,21 +189,259 @@ func Test_returnEligibleDeployments(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := returnEligibleDeployments(tt.args.clientset, tt.args.nsList); !reflect.DeepEqual(got, tt.want) {
+			got, err := returnEligibleDeployments(tt.args.clientset, tt.args.nsList)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("returnEligibleDeployments() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("returnEligibleDeployments() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+func Test_returnEligibleStatefulSets(t *testing.T) {
+	var replicas int32 = 3
+	statefulSet := appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+		},
+	}
+
+	forbidden := fake.NewSimpleClientset(statefulSet.DeepCopy())
+	forbidden.PrependReactor("list", "statefulsets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "statefulsets"}, "", fmt.Errorf("cannot list resource \"statefulsets\" in the Namespace \"default\""))
+	})
+
+	type args struct {
+		clientset kubernetes.Interface
+		nsList    []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []appsv1.StatefulSet
+		wantErr bool
+	}{
+		{
+			name: "Matched StatefulSet objects should be returned",
+			args: args{
+				clientset: fake.NewSimpleClientset(statefulSet.DeepCopy()),
+				nsList:    []string{"default"},
+			},
+			want: []appsv1.StatefulSet{statefulSet},
+		},
+		{
+			name: "StatefulSets in other Namespaces should not be returned",
+			args: args{
+				clientset: fake.NewSimpleClientset(statefulSet.DeepCopy()),
+				nsList:    []string{"other"},
+			},
+			want: nil,
+		},
+		{
+			name: "Namespaces where StatefulSets can't be listed should be skipped",
+			args: args{
+				clientset: forbidden,
+				nsList:    []string{"default"},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := returnEligibleStatefulSets(tt.args.clientset, tt.args.nsList)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("returnEligibleStatefulSets() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("returnEligibleStatefulSets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_returnEligibleDaemonSets(t *testing.T) {
+	daemonSet := appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+		},
+	}
+
+	forbidden := fake.NewSimpleClientset(daemonSet.DeepCopy())
+	forbidden.PrependReactor("list", "daemonsets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "daemonsets"}, "", fmt.Errorf("cannot list resource \"daemonsets\" in the Namespace \"default\""))
+	})
+
+	tests := []struct {
+		name      string
+		clientset kubernetes.Interface
+		want      []appsv1.DaemonSet
+		wantErr   bool
+	}{
+		{
+			name:      "Matched DaemonSet objects should be returned",
+			clientset: fake.NewSimpleClientset(daemonSet.DeepCopy()),
+			want:      []appsv1.DaemonSet{daemonSet},
+		},
+		{
+			name:      "Namespaces where DaemonSets can't be listed should be skipped",
+			clientset: forbidden,
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := returnEligibleDaemonSets(tt.clientset, []string{"default"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("returnEligibleDaemonSets() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("returnEligibleDaemonSets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_preferredHPAVersion(t *testing.T) {
+	served := func(versions ...string) kubernetes.Interface {
+		clientset := fake.NewSimpleClientset()
+		for _, version := range versions {
+			clientset.Resources = append(clientset.Resources, &metav1.APIResourceList{
+				GroupVersion: version,
+				APIResources: []metav1.APIResource{{Name: "horizontalpodautoscalers"}},
+			})
+		}
+		return clientset
+	}
+
+	tests := []struct {
+		name      string
+		clientset kubernetes.Interface
+		want      string
+	}{
+		{
+			name:      "autoscaling/v2 should be preferred when served",
+			clientset: served("autoscaling/v1", "autoscaling/v2beta2", "autoscaling/v2"),
+			want:      "autoscaling/v2",
+		},
+		{
+			name:      "autoscaling/v2beta2 should be used when autoscaling/v2 isn't served",
+			clientset: served("autoscaling/v1", "autoscaling/v2beta2"),
+			want:      "autoscaling/v2beta2",
+		},
+		{
+			name:      "autoscaling/v1 should be used when discovery reports nothing newer",
+			clientset: served(),
+			want:      "autoscaling/v1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := preferredHPAVersion(tt.clientset); got != tt.want {
+				t.Errorf("preferredHPAVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_returnHorizontalPodAutoscalers(t *testing.T) {
 	var replicas int32 = 1
+	var utilization int32 = 60
+	var window int32 = 300
+	memory := resource.MustParse("500Mi")
+	defaultMetrics := []autoscalingv2.MetricSpec{cpuUtilizationMetric(80)}
+	behavior := &autoscalingv2.HorizontalPodAutoscalerBehavior{
+		ScaleDown: &autoscalingv2.HPAScalingRules{StabilizationWindowSeconds: &window},
+	}
+	metrics := []autoscalingv2.MetricSpec{
+		cpuUtilizationMetric(utilization),
+		{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name:   corev1.ResourceMemory,
+				Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType, AverageValue: &memory},
+			},
+		},
+	}
+	scaleTargetRef := autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", APIVersion: "apps/v1", Name: "foo"}
+	v2 := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: scaleTargetRef,
+			MaxReplicas:    3,
+			Metrics:        metrics,
+			Behavior:       behavior,
+		},
+	}
+	v2beta2 := &autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "default"},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference(scaleTargetRef),
+			MaxReplicas:    3,
+			Metrics: []autoscalingv2beta2.MetricSpec{
+				{
+					Type: autoscalingv2beta2.ResourceMetricSourceType,
+					Resource: &autoscalingv2beta2.ResourceMetricSource{
+						Name:   corev1.ResourceCPU,
+						Target: autoscalingv2beta2.MetricTarget{Type: autoscalingv2beta2.UtilizationMetricType, AverageUtilization: &utilization},
+					},
+				},
+			},
+		},
+	}
+
+	workload := &Workload{
+		Kind:      KindDeployment,
+		Name:      "foo",
+		Namespace: "default",
+		Labels:    "app=foo,app.kubernetes.io/instance=foo",
+	}
+	labelled := &autoscalingv1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app":                        "foo",
+				"app.kubernetes.io/instance": "foo",
+			},
+		},
+		Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+			MinReplicas: &replicas,
+			MaxReplicas: 5,
+		},
+	}
+	targeting := func(kind, apiVersion, name string) *autoscalingv1.HorizontalPodAutoscaler {
+		return &autoscalingv1.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "default",
+			},
+			Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{
+					Kind:       kind,
+					APIVersion: apiVersion,
+					Name:       name,
+				},
+				MinReplicas: &replicas,
+				MaxReplicas: 3,
+			},
+		}
+	}
 
 	type args struct {
-		clientset      kubernetes.Interface
-		application    string
-		ns             string
-		labelsAsString string
+		clientset     kubernetes.Interface
+		w             *Workload
+		apiVersion    string
+		labelFallback bool
 	}
 	tests := []struct {
 		name string
@@ -107,40 +449,138 @@ func Test_returnHorizontalPodAutoscalers(t *testing.T) {
 		want *hpaDescription
 	}{
 		{
-			name: "Matched HorizontalPodAutoscaler objects should be returned",
+			name: "A HorizontalPodAutoscaler targeting the workload should be returned even without labels",
 			args: args{
-				clientset: fake.NewSimpleClientset(
-					&autoscalingv1.HorizontalPodAutoscaler{
-						ObjectMeta: metav1.ObjectMeta{
-							Name:      "foo",
-							Namespace: "default",
-							Labels: map[string]string{
-								"app":                        "foo",
-								"app.kubernetes.io/instance": "foo",
-							},
-						},
-						Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
-							MinReplicas: &replicas,
-							MaxReplicas: 5,
-						},
-					},
-				),
-				application:    "foo",
-				ns:             "default",
-				labelsAsString: "app=foo, app.kubernetes.io/instance=foo",
+				clientset:  fake.NewSimpleClientset(targeting("Deployment", "apps/v1", "foo")),
+				w:          workload,
+				apiVersion: "autoscaling/v1",
+			},
+			want: &hpaDescription{
+				application: "foo",
+				max:         3,
+				metrics:     defaultMetrics,
+				min:         1,
+				name:        "bar",
+				namespace:   "default",
+			},
+		},
+		{
+			name: "A HorizontalPodAutoscaler targeting another workload should not be returned even with matching labels",
+			args: args{
+				clientset: fake.NewSimpleClientset(func() *autoscalingv1.HorizontalPodAutoscaler {
+					hpa := targeting("Deployment", "apps/v1", "baz")
+					hpa.Labels = labelled.Labels
+					return hpa
+				}()),
+				w:          workload,
+				apiVersion: "autoscaling/v1",
+			},
+			want: &hpaDescription{},
+		},
+		{
+			name: "A HorizontalPodAutoscaler targeting another kind with the same name should not be returned",
+			args: args{
+				clientset:  fake.NewSimpleClientset(targeting("StatefulSet", "apps/v1", "foo")),
+				w:          workload,
+				apiVersion: "autoscaling/v1",
+			},
+			want: &hpaDescription{},
+		},
+		{
+			name: "A HorizontalPodAutoscaler matched only by labels should not be returned by default",
+			args: args{
+				clientset:  fake.NewSimpleClientset(labelled),
+				w:          workload,
+				apiVersion: "autoscaling/v1",
+			},
+			want: &hpaDescription{},
+		},
+		{
+			name: "Matched HorizontalPodAutoscaler objects should be returned when falling back to labels",
+			args: args{
+				clientset:     fake.NewSimpleClientset(labelled),
+				w:             workload,
+				apiVersion:    "autoscaling/v1",
+				labelFallback: true,
 			},
 			want: &hpaDescription{
 				application: "foo",
 				max:         5,
+				metrics:     defaultMetrics,
 				min:         1,
 				name:        "foo",
 				namespace:   "default",
 			},
 		},
+		{
+			name: "An autoscaling/v2 HorizontalPodAutoscaler should be returned with its metrics and behavior",
+			args: args{
+				clientset:  fake.NewSimpleClientset(v2),
+				w:          workload,
+				apiVersion: "autoscaling/v2",
+			},
+			want: &hpaDescription{
+				application: "foo",
+				behavior:    behavior,
+				max:         3,
+				metrics:     metrics,
+				min:         1,
+				name:        "bar",
+				namespace:   "default",
+			},
+		},
+		{
+			name: "An autoscaling/v1 HorizontalPodAutoscaler should be returned with the conditions kept in its annotations",
+			args: args{
+				clientset: fake.NewSimpleClientset(func() *autoscalingv1.HorizontalPodAutoscaler {
+					hpa := targeting("Deployment", "apps/v1", "foo")
+					hpa.Annotations = map[string]string{
+						hpaConditionsAnnotation: `[{"type":"ScalingLimited","status":"True","reason":"TooManyReplicas"}]`,
+					}
+					hpa.Status = autoscalingv1.HorizontalPodAutoscalerStatus{CurrentReplicas: 3, DesiredReplicas: 3}
+					return hpa
+				}()),
+				w:          workload,
+				apiVersion: "autoscaling/v1",
+			},
+			want: &hpaDescription{
+				application: "foo",
+				conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{
+					{Type: autoscalingv2.ScalingLimited, Status: corev1.ConditionTrue, Reason: "TooManyReplicas"},
+				},
+				currentReplicas: 3,
+				desiredReplicas: 3,
+				max:             3,
+				metrics:         defaultMetrics,
+				min:             1,
+				name:            "bar",
+				namespace:       "default",
+			},
+		},
+		{
+			name: "An autoscaling/v2beta2 HorizontalPodAutoscaler should be converted to autoscaling/v2",
+			args: args{
+				clientset:  fake.NewSimpleClientset(v2beta2),
+				w:          workload,
+				apiVersion: "autoscaling/v2beta2",
+			},
+			want: &hpaDescription{
+				application: "foo",
+				max:         3,
+				metrics:     []autoscalingv2.MetricSpec{cpuUtilizationMetric(utilization)},
+				min:         1,
+				name:        "bar",
+				namespace:   "default",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := returnHorizontalPodAutoscalers(tt.args.clientset, tt.args.application, tt.args.ns, tt.args.labelsAsString); !reflect.DeepEqual(got, tt.want) {
+			hpas, err := listHorizontalPodAutoscalers(tt.args.clientset, tt.args.w.Namespace, tt.args.apiVersion)
+			if err != nil {
+				t.Fatalf("listHorizontalPodAutoscalers() error = %v", err)
+			}
+			if got := returnHorizontalPodAutoscalers(hpas, tt.args.w, tt.args.labelFallback); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("returnHorizontalPodAutoscalers() = %v, want %v", got, tt.want)
 			}
 		})
@@ -151,56 +591,255 @@ func Test_returnPodDisruptionBudgets(t *testing.T) {
 	var maxUnavailable intstr.IntOrString = intstr.IntOrString{IntVal: 1}
 	var minAvailable intstr.IntOrString = intstr.IntOrString{IntVal: 1}
 
+	workload := &Workload{
+		Kind:      KindDeployment,
+		Name:      "foo",
+		Namespace: "default",
+		Selectors: map[string]string{"app": "foo"},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					"app":                        "foo",
+					"app.kubernetes.io/instance": "foo",
+				},
+			},
+		},
+	}
+	scaled := &Workload{}
+	*scaled = *workload
+	scaled.Replicas = 3
+	percentage := intstr.FromString("50%")
+
+	pdb := func(selector *metav1.LabelSelector, objectLabels map[string]string) *policyv1.PodDisruptionBudget {
+		return &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+				Labels:    objectLabels,
+			},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				MaxUnavailable: &maxUnavailable,
+				MinAvailable:   &minAvailable,
+				Selector:       selector,
+			},
+		}
+	}
+	matched := &pdbDescription{
+		application: "foo",
+		availabilityConfig: map[string]int32{
+			"maxUnavailable": 1,
+			"minAvailable":   1,
+		},
+		maxUnavailable: &maxUnavailable,
+		minAvailable:   &minAvailable,
+		name:           "foo",
+		namespace:      "default",
+	}
+
 	type args struct {
-		clientset      kubernetes.Interface
-		application    string
-		ns             string
-		labelsAsString string
+		clientset kubernetes.Interface
+		w         *Workload
 	}
 	tests := []struct {
 		name string
 		args args
-		want *pdbDescription
+		want []*pdbDescription
 	}{
 		{
-			name: "Matched PodDisruptionBudget objects should be returned",
+			name: "A PodDisruptionBudget whose selector matches the Pod template should be returned even without labels of its own",
 			args: args{
-				clientset: fake.NewSimpleClientset(
-					&policyv1.PodDisruptionBudget{
-						ObjectMeta: metav1.ObjectMeta{
-							Name:      "foo",
-							Namespace: "default",
-							Labels: map[string]string{
-								"app":                        "foo",
-								"app.kubernetes.io/instance": "foo",
-							},
-						},
-						Spec: policyv1.PodDisruptionBudgetSpec{
-							MaxUnavailable: &maxUnavailable,
-							MinAvailable:   &minAvailable,
-						},
+				clientset: fake.NewSimpleClientset(pdb(&metav1.LabelSelector{
+					MatchLabels: map[string]string{"app.kubernetes.io/instance": "foo"},
+				}, nil)),
+				w: workload,
+			},
+			want: []*pdbDescription{matched},
+		},
+		{
+			name: "A PodDisruptionBudget whose selector uses matchExpressions should be returned when they match",
+			args: args{
+				clientset: fake.NewSimpleClientset(pdb(&metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"foo", "bar"}},
 					},
+				}, nil)),
+				w: workload,
+			},
+			want: []*pdbDescription{matched},
+		},
+		{
+			name: "A PodDisruptionBudget sharing the workload's labels but selecting other Pods should not be returned",
+			args: args{
+				clientset: fake.NewSimpleClientset(pdb(&metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "bar"},
+				}, map[string]string{
+					"app":                        "foo",
+					"app.kubernetes.io/instance": "foo",
+				})),
+				w: workload,
+			},
+			want: nil,
+		},
+		{
+			name: "Every PodDisruptionBudget covering the workload should be returned",
+			args: args{
+				clientset: fake.NewSimpleClientset(
+					pdb(&metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}, nil),
+					func() *policyv1.PodDisruptionBudget {
+						p := pdb(&metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/instance": "foo"}}, nil)
+						p.Name = "foo-2"
+						return p
+					}(),
 				),
-				application:    "foo",
-				ns:             "default",
-				labelsAsString: "app=foo, app.kubernetes.io/instance=foo",
+				w: workload,
 			},
-			want: &pdbDescription{
-				application: "foo",
-				availabilityConfig: map[string]int32{
-					"maxUnavailable": 1,
-					"minAvailable":   1,
+			want: []*pdbDescription{
+				matched,
+				{
+					application:        "foo",
+					availabilityConfig: matched.availabilityConfig,
+					maxUnavailable:     &maxUnavailable,
+					minAvailable:       &minAvailable,
+					name:               "foo-2",
+					namespace:          "default",
+				},
+			},
+		},
+		{
+			name: "Percentages should be resolved against the workload's replicas and rounded up",
+			args: args{
+				clientset: fake.NewSimpleClientset(&policyv1.PodDisruptionBudget{
+					ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+					Spec: policyv1.PodDisruptionBudgetSpec{
+						MinAvailable: &percentage,
+						Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+					},
+				}),
+				w: scaled,
+			},
+			want: []*pdbDescription{
+				{
+					application:        "foo",
+					availabilityConfig: map[string]int32{"minAvailable": 2},
+					minAvailable:       &percentage,
+					name:               "foo",
+					namespace:          "default",
 				},
-				name:      "foo",
-				namespace: "default",
 			},
 		},
+		{
+			name: "A PodDisruptionBudget without minAvailable or maxUnavailable should be returned",
+			args: args{
+				clientset: fake.NewSimpleClientset(&policyv1.PodDisruptionBudget{
+					ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+					Spec: policyv1.PodDisruptionBudgetSpec{
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+					},
+				}),
+				w: workload,
+			},
+			want: []*pdbDescription{
+				{
+					application:        "foo",
+					availabilityConfig: map[string]int32{},
+					name:               "foo",
+					namespace:          "default",
+				},
+			},
+		},
+		{
+			name: "A PodDisruptionBudget without a selector should not be returned",
+			args: args{
+				clientset: fake.NewSimpleClientset(pdb(nil, map[string]string{"app": "foo"})),
+				w:         workload,
+			},
+			want: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := returnPodDisruptionBudgets(tt.args.clientset, tt.args.application, tt.args.ns, tt.args.labelsAsString); !reflect.DeepEqual(got, tt.want) {
+			pdbs, err := listPodDisruptionBudgets(tt.args.clientset, tt.args.w.Namespace)
+			if err != nil {
+				t.Fatalf("listPodDisruptionBudgets() error = %v", err)
+			}
+			if got := returnPodDisruptionBudgets(pdbs, tt.args.w); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("returnPodDisruptionBudgets() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func Test_returnReadyPods(t *testing.T) {
+	controlledBy := func(obj metav1.Object, kind string, uid types.UID) {
+		isController := true
+		obj.SetOwnerReferences([]metav1.OwnerReference{{Kind: kind, Name: "owner", UID: uid, Controller: &isController}})
+	}
+	deployment := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", UID: "deployment"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+		},
+	}
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo-1", Namespace: "default", UID: "replicaset", Labels: map[string]string{"app": "foo"}},
+	}
+	controlledBy(replicaSet, "Deployment", "deployment")
+	owned := testPod("foo-1", "a1", true)
+	controlledBy(owned, "ReplicaSet", "replicaset")
+	shared := testPod("bar-0", "a1", true)
+	controlledBy(shared, "StatefulSet", "statefulset")
+	unlabelled := testPod("baz-0", "a1", true)
+	unlabelled.Labels = map[string]string{"app": "baz"}
+
+	tests := []struct {
+		name        string
+		w           *Workload
+		pods        []corev1.Pod
+		replicaSets []appsv1.ReplicaSet
+		want        []string
+	}{
+		{
+			name: "Pods should be matched using matchExpressions",
+			w: &Workload{
+				Kind:      KindDeployment,
+				Name:      "foo",
+				Namespace: "default",
+				Selector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"foo"}},
+					},
+				},
+			},
+			pods: []corev1.Pod{*testPod("foo-1", "a1", true), *testPod("foo-2", "a1", false), *unlabelled},
+			want: []string{"foo-1"},
+		},
+		{
+			name:        "Pods controlled by another workload sharing the labels should not be returned",
+			w:           buildDeploymentWorkload(deployment),
+			pods:        []corev1.Pod{*owned, *shared},
+			replicaSets: []appsv1.ReplicaSet{*replicaSet},
+			want:        []string{"foo-1"},
+		},
+		{
+			name: "A workload without a selector should not select any Pods",
+			w:    &Workload{Kind: KindDeployment, Name: "foo", Namespace: "default"},
+			pods: []corev1.Pod{*testPod("foo-1", "a1", true)},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pods, err := returnReadyPods(tt.pods, tt.replicaSets, tt.w)
+			if err != nil {
+				t.Fatalf("returnReadyPods() error = %v", err)
+			}
+			var got []string
+			for _, pod := range pods {
+				got = append(got, pod.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("returnReadyPods() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import "fmt"
+
+// Config controls which rules are carried out and how
+// It is usually read from the paranoidaf config file
+type Config struct {
+	Rules      map[string]RuleConfig `mapstructure:"rules" json:"rules,omitempty"`
+	Thresholds Thresholds            `mapstructure:"thresholds" json:"thresholds"`
+	// HPALabelFallback matches HorizontalPodAutoscalers using the workload's selector
+	// labels when none target it using spec.scaleTargetRef
+	HPALabelFallback bool `mapstructure:"hpaLabelFallback" json:"hpaLabelFallback"`
+}
+
+// RuleConfig overrides the behavior of a single Rule
+type RuleConfig struct {
+	// Enabled turns a Rule on or off - rules are enabled unless set to false
+	Enabled *bool `mapstructure:"enabled" json:"enabled,omitempty"`
+	// Severity replaces the Severity of findings produced by the Rule
+	Severity Severity `mapstructure:"severity" json:"severity,omitempty"`
+}
+
+// Thresholds holds the values rules compare workloads against
+type Thresholds struct {
+	// MinReplicas is the fewest replicas a workload without a HorizontalPodAutoscaler should run
+	MinReplicas int32 `mapstructure:"minReplicas" json:"minReplicas"`
+	// MinLivenessFailureSeconds is the shortest time a liveness probe should be allowed to
+	// fail for, i.e. failureThreshold x periodSeconds, before its container is restarted
+	MinLivenessFailureSeconds int32 `mapstructure:"minLivenessFailureSeconds" json:"minLivenessFailureSeconds"`
+	// MaxLimitRequestRatio is the most a container's cpu or memory limit should exceed its
+	// request by, in the same way as a LimitRange's maxLimitRequestRatio
+	MaxLimitRequestRatio float64 `mapstructure:"maxLimitRequestRatio" json:"maxLimitRequestRatio"`
+}
+
+// DefaultConfig returns a Config with every Rule enabled and default thresholds
+func DefaultConfig() *Config {
+	return &Config{
+		Rules: map[string]RuleConfig{},
+		Thresholds: Thresholds{
+			MinReplicas:               2,
+			MinLivenessFailureSeconds: 10,
+			MaxLimitRequestRatio:      4,
+		},
+	}
+}
+
+// Validate makes sure the Config only references registered rules and valid values
+func (c *Config) Validate() error {
+	registered := map[string]bool{}
+	for _, r := range Rules() {
+		registered[r.ID()] = true
+	}
+	for id, rc := range c.Rules {
+		if !registered[id] {
+			return fmt.Errorf("unknown rule %q in config", id)
+		}
+		if rc.Severity != "" {
+			if _, err := ParseSeverity(string(rc.Severity)); err != nil {
+				return fmt.Errorf("invalid severity for rule %q: %w", id, err)
+			}
+		}
+	}
+	if c.Thresholds.MinReplicas < 1 {
+		return fmt.Errorf("thresholds.minReplicas must be at least 1, got %v", c.Thresholds.MinReplicas)
+	}
+	if c.Thresholds.MinLivenessFailureSeconds < 0 {
+		return fmt.Errorf("thresholds.minLivenessFailureSeconds must not be negative, got %v", c.Thresholds.MinLivenessFailureSeconds)
+	}
+	if c.Thresholds.MaxLimitRequestRatio < 1 {
+		return fmt.Errorf("thresholds.maxLimitRequestRatio must be at least 1, got %v", c.Thresholds.MaxLimitRequestRatio)
+	}
+	return nil
+}
+
+// enabled returns whether or not the Rule with the given ID should be carried out
+func (c *Config) enabled(id string) bool {
+	rc, ok := c.Rules[id]
+	return !ok || rc.Enabled == nil || *rc.Enabled
+}
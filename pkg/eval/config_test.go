@@ -0,0 +1,121 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *Config
+		wantErr bool
+	}{
+		{
+			name:   "The default Config should be valid",
+			config: DefaultConfig(),
+		},
+		{
+			name: "A Config referencing an unknown rule should be invalid",
+			config: &Config{
+				Rules:      map[string]RuleConfig{"foo": {}},
+				Thresholds: DefaultConfig().Thresholds,
+			},
+			wantErr: true,
+		},
+		{
+			name: "A Config with an unknown severity should be invalid",
+			config: &Config{
+				Rules:      map[string]RuleConfig{RuleMinReplicas: {Severity: "critical"}},
+				Thresholds: DefaultConfig().Thresholds,
+			},
+			wantErr: true,
+		},
+		{
+			name: "A Config with a minReplicas threshold below 1 should be invalid",
+			config: &Config{
+				Thresholds: Thresholds{MinReplicas: 0},
+			},
+			wantErr: true,
+		},
+		{
+			name: "A Config with a negative minLivenessFailureSeconds threshold should be invalid",
+			config: &Config{
+				Thresholds: Thresholds{MinReplicas: 2, MinLivenessFailureSeconds: -1, MaxLimitRequestRatio: 4},
+			},
+			wantErr: true,
+		},
+		{
+			name: "A Config with a maxLimitRequestRatio threshold below 1 should be invalid",
+			config: &Config{
+				Thresholds: Thresholds{MinReplicas: 2, MaxLimitRequestRatio: 0.5},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Config.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_evaluateWorkload_config(t *testing.T) {
+	disabled := false
+	var replicas int32 = 2
+	w := buildDeploymentWorkload(appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+		},
+	})
+	cfg := &Config{
+		Rules: map[string]RuleConfig{
+			RuleHPAConfigured: {Enabled: &disabled},
+			RulePDBConfigured: {Severity: SeverityError},
+		},
+		Thresholds: Thresholds{MinReplicas: 3},
+	}
+	report := evaluateWorkload(w, newClusterState(fake.NewSimpleClientset(), cfg))
+
+	got := map[string]Finding{}
+	for _, f := range report.Findings {
+		got[f.RuleID] = f
+	}
+	if _, ok := got[RuleHPAConfigured]; ok {
+		t.Errorf("evaluateWorkload() carried out disabled rule %s", RuleHPAConfigured)
+	}
+	if got[RulePDBConfigured].Severity != SeverityError {
+		t.Errorf("evaluateWorkload() severity = %v, want %v", got[RulePDBConfigured].Severity, SeverityError)
+	}
+	if got[RuleMinReplicas].Passed {
+		t.Errorf("evaluateWorkload() passed %s with 2 replicas and a threshold of 3", RuleMinReplicas)
+	}
+}
@@ -23,10 +23,12 @@ package eval
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/echoboomer/paranoidaf/pkg/common"
 	log "github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
@@ -38,29 +40,43 @@ var nsFilter = []string{"kube-system", "kube-node-lease", "kube-public"}
 type UGPrepOptions struct {
 	ClusterName string
 	Namespace   string
+	// Config controls which rules are carried out - DefaultConfig is used if nil
+	Config *Config
 }
 
-// Check carries out various processes related to the check
-func Check(config *rest.Config, clientset kubernetes.Interface, o *UGPrepOptions) {
+// Check carries out various processes related to the check and returns a Report
+// describing what was found
+// An error is only returned if the cluster could not be talked to
+// config is used to find custom resources like Argo Rollouts and may be nil to skip them
+func Check(config *rest.Config, clientset kubernetes.Interface, o *UGPrepOptions) (*Report, error) {
 	// Friendly info
 	log.Infof("Checking cluster %s...", o.ClusterName)
 
+	// Make sure the cluster is reachable before doing anything else
+	if _, err := clientset.Discovery().ServerVersion(); err != nil {
+		return nil, fmt.Errorf("could not reach cluster %s: %w", o.ClusterName, err)
+	}
+
 	// If a Namespace is passed in, we only check that one
 	// Otherwise, we check all non-filtered Namespaces
-	var nsList []string
+	// DaemonSets are checked in filtered Namespaces too, since that's where cluster
+	// components like CNI and logging agents run
+	var nsList, daemonSetNSList []string
 	if o.Namespace != "" {
 		nsList = append(nsList, o.Namespace)
+		daemonSetNSList = nsList
 	} else {
 		// Build a list of Namespaces
 		namespaces, err := clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
 		if err != nil {
-			log.Errorf("Error building list of Namespaces: %s", err)
+			return nil, fmt.Errorf("error building list of Namespaces: %w", err)
 		}
 
 		// Build a list of eligible Namespaces
 		for _, ns := range namespaces.Items {
 			nsList = append(nsList, ns.Name)
 		}
+		daemonSetNSList = append([]string(nil), nsList...)
 
 		// Filter out unwanted Namespaces
 		// kube-public, kube-system, kube-node-lease
@@ -69,12 +85,61 @@ func Check(config *rest.Config, clientset kubernetes.Interface, o *UGPrepOptions
 		}
 	}
 
-	// Establish qualifying Deployments as the basis for the check
-	deployments := returnEligibleDeployments(clientset, nsList)
-	if len(deployments) == 0 {
-		log.Infof("Didn't find any Deployments in these Namespaces: %s", nsList)
+	// Establish qualifying workloads as the basis for the check
+	var workloads []*Workload
+	deployments, err := returnEligibleDeployments(clientset, nsList)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deployments {
+		workloads = append(workloads, buildDeploymentWorkload(d))
+	}
+	statefulSets, err := returnEligibleStatefulSets(clientset, nsList)
+	if err != nil {
+		return nil, err
+	}
+	for _, ss := range statefulSets {
+		workloads = append(workloads, buildStatefulSetWorkload(ss))
+	}
+	daemonSets, err := returnEligibleDaemonSets(clientset, daemonSetNSList)
+	if err != nil {
+		return nil, err
+	}
+	for _, ds := range daemonSets {
+		workloads = append(workloads, buildDaemonSetWorkload(ds))
+	}
+
+	// Argo Rollouts are custom resources, so they're found using the dynamic client
+	if config != nil && rolloutsServed(clientset) {
+		dynamicClient, err := dynamic.NewForConfig(config)
+		if err != nil {
+			return nil, fmt.Errorf("error creating dynamic client: %w", err)
+		}
+		rollouts, err := returnEligibleRollouts(dynamicClient, nsList)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rollouts {
+			w, err := buildRolloutWorkload(r)
+			if err != nil {
+				log.Errorf("Error: %s", err)
+				continue
+			}
+			workloads = append(workloads, w)
+		}
+	}
+	if len(workloads) == 0 {
+		log.Infof("Didn't find any workloads in these Namespaces: %s", nsList)
 	}
 
 	// Run it
-	checkDeployments(clientset, deployments)
+	cfg := o.Config
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+	return &Report{
+		ClusterName: o.ClusterName,
+		Namespaces:  nsList,
+		Workloads:   checkWorkloads(newClusterState(clientset, cfg), workloads),
+	}, nil
 }
@@ -0,0 +1,66 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheck_filteredNamespaces(t *testing.T) {
+	var replicas int32 = 2
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: "kube-system"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas, Selector: selector},
+		},
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "cni", Namespace: "kube-system"},
+			Spec:       appsv1.DaemonSetSpec{Selector: selector},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas, Selector: selector},
+		},
+	)
+
+	report, err := Check(nil, clientset, &UGPrepOptions{ClusterName: "test"})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	var got []string
+	for _, w := range report.Workloads {
+		got = append(got, workloadKey(&Workload{Kind: w.Kind, Namespace: w.Namespace, Name: w.Name}))
+	}
+	want := []string{"Deployment/default/foo", "DaemonSet/kube-system/cni"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Check() evaluated %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import "fmt"
+
+// Rule is a single check carried out against each workload
+// Rules are registered using Register and are carried out in the order
+// they were registered
+type Rule interface {
+	// ID returns a stable, unique identifier for the Rule
+	ID() string
+	// Description returns a short explanation of what the Rule checks for
+	Description() string
+	// Severity returns how serious it is when the Rule fails
+	Severity() Severity
+	// Evaluate checks a workload and returns any findings
+	// Rules that don't apply to the workload should return nothing
+	Evaluate(w *Workload, s *ClusterState) []Finding
+}
+
+// registry holds every registered Rule in the order it was registered
+var registry []Rule
+
+// Register adds a Rule to the set of rules carried out against each workload
+// It panics if the Rule has no ID or if a Rule with the same ID is already registered
+func Register(r Rule) {
+	if r.ID() == "" {
+		panic("eval: Register called with a Rule that has no ID")
+	}
+	for _, existing := range registry {
+		if existing.ID() == r.ID() {
+			panic(fmt.Sprintf("eval: Register called twice for Rule %s", r.ID()))
+		}
+	}
+	registry = append(registry, r)
+}
+
+// Rules returns every registered Rule in the order it was registered
+func Rules() []Rule {
+	return append([]Rule{}, registry...)
+}
+
+// NewFinding returns a Finding for a Rule evaluated against a workload
+// The Finding uses the Rule's Severity
+func NewFinding(r Rule, w *Workload, passed bool, message string, suggestions ...string) Finding {
+	return Finding{
+		RuleID:      r.ID(),
+		Severity:    r.Severity(),
+		Passed:      passed,
+		Kind:        w.Kind,
+		Workload:    w.Name,
+		Namespace:   w.Namespace,
+		Message:     message,
+		Suggestions: suggestions,
+	}
+}
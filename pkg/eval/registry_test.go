@@ -0,0 +1,110 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// testRule fails every workload
+type testRule struct {
+	id string
+}
+
+func (r testRule) ID() string { return r.id }
+
+func (testRule) Description() string { return "A rule used for testing." }
+
+func (testRule) Severity() Severity { return SeverityInfo }
+
+func (r testRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	return []Finding{NewFinding(r, w, false, "testing")}
+}
+
+func TestRegister(t *testing.T) {
+	original := registry
+	defer func() { registry = original }()
+
+	tests := []struct {
+		name      string
+		rule      Rule
+		wantPanic bool
+	}{
+		{
+			name: "A new Rule should be registered",
+			rule: testRule{id: "test-rule"},
+		},
+		{
+			name:      "A Rule with an ID that is already registered should panic",
+			rule:      testRule{id: RuleHPAConfigured},
+			wantPanic: true,
+		},
+		{
+			name:      "A Rule without an ID should panic",
+			rule:      testRule{},
+			wantPanic: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("Register() panic = %v, wantPanic %v", r, tt.wantPanic)
+				}
+			}()
+			Register(tt.rule)
+		})
+	}
+}
+
+func Test_evaluateWorkload(t *testing.T) {
+	original := registry
+	defer func() { registry = original }()
+	Register(testRule{id: "test-rule"})
+
+	var replicas int32 = 2
+	w := buildDeploymentWorkload(appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+		},
+	})
+	report := evaluateWorkload(w, newClusterState(fake.NewSimpleClientset(), DefaultConfig()))
+
+	var found bool
+	for _, f := range report.Findings {
+		if f.RuleID == "test-rule" {
+			found = true
+			if f.Workload != "foo" || f.Namespace != "default" || f.Severity != SeverityInfo {
+				t.Errorf("evaluateWorkload() returned unexpected finding %+v", f)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("evaluateWorkload() did not carry out registered Rule test-rule")
+	}
+}
@@ -0,0 +1,267 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"fmt"
+	"strings"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Severity describes how serious a Finding is
+type Severity string
+
+const (
+	// SeverityInfo is used for findings that are worth knowing about but are not a risk
+	SeverityInfo Severity = "info"
+	// SeverityWarning is used for findings that may cause interruptions
+	SeverityWarning Severity = "warning"
+	// SeverityError is used for findings that will very likely cause interruptions
+	SeverityError Severity = "error"
+)
+
+// severityRank orders severities from least to most serious
+var severityRank = map[Severity]int{
+	SeverityInfo:    1,
+	SeverityWarning: 2,
+	SeverityError:   3,
+}
+
+// ParseSeverity returns the Severity matching the given string
+func ParseSeverity(s string) (Severity, error) {
+	severity := Severity(s)
+	if _, ok := severityRank[severity]; !ok {
+		return "", fmt.Errorf("unknown severity %q, must be one of %s, %s, %s", s, SeverityInfo, SeverityWarning, SeverityError)
+	}
+	return severity, nil
+}
+
+// AtLeast returns whether or not the Severity is as serious as or more serious than threshold
+func (s Severity) AtLeast(threshold Severity) bool {
+	return severityRank[s] >= severityRank[threshold]
+}
+
+// Finding is the result of a single check against a single workload
+// Subject tells apart findings from a Rule that returns several for the same workload
+// or container, e.g. the topology key or resource they are about
+type Finding struct {
+	RuleID      string   `json:"ruleID"`
+	Severity    Severity `json:"severity"`
+	Passed      bool     `json:"passed"`
+	Kind        string   `json:"kind"`
+	Workload    string   `json:"workload"`
+	Namespace   string   `json:"namespace"`
+	Container   string   `json:"container,omitempty"`
+	Subject     string   `json:"subject,omitempty"`
+	Message     string   `json:"message"`
+	Suggestions []string `json:"suggestions,omitempty"`
+	HPA         string   `json:"hpa,omitempty"`
+	PDB         string   `json:"pdb,omitempty"`
+}
+
+// HPAInfo describes the HorizontalPodAutoscaler matched to a workload
+type HPAInfo struct {
+	Name        string      `json:"name"`
+	MinReplicas int32       `json:"minReplicas"`
+	MaxReplicas int32       `json:"maxReplicas"`
+	Metrics     []HPAMetric `json:"metrics"`
+	// Behavior holds spec.behavior as configured, or nil if the defaults apply
+	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+	// CurrentReplicas, DesiredReplicas and Conditions are read from the status
+	CurrentReplicas int32                                            `json:"currentReplicas"`
+	DesiredReplicas int32                                            `json:"desiredReplicas"`
+	Conditions      []autoscalingv2.HorizontalPodAutoscalerCondition `json:"conditions,omitempty"`
+}
+
+// Condition returns the status condition of the given type, or nil if it hasn't been reported
+func (h *HPAInfo) Condition(conditionType autoscalingv2.HorizontalPodAutoscalerConditionType) *autoscalingv2.HorizontalPodAutoscalerCondition {
+	for i := range h.Conditions {
+		if h.Conditions[i].Type == conditionType {
+			return &h.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// HPAMetric describes one of the metrics a HorizontalPodAutoscaler scales on
+type HPAMetric struct {
+	// Type is the metric source, e.g. Resource or External
+	Type string `json:"type"`
+	// Name is the resource or metric name, e.g. cpu or requests-per-second
+	Name string `json:"name"`
+	// Container is only set for ContainerResource metrics
+	Container string `json:"container,omitempty"`
+	// TargetType is Utilization, Value or AverageValue
+	TargetType string `json:"targetType"`
+	// Target is the target value, e.g. 80% or 500m
+	Target string `json:"target"`
+}
+
+// String returns a short description of the metric, e.g. "cpu (Resource) at 80% average utilization"
+func (m HPAMetric) String() string {
+	name := m.Name
+	if m.Container != "" {
+		name = fmt.Sprintf("%s of container %s", m.Name, m.Container)
+	}
+	target := m.Target + " value"
+	switch m.TargetType {
+	case string(autoscalingv2.UtilizationMetricType):
+		target = m.Target + " average utilization"
+	case string(autoscalingv2.AverageValueMetricType):
+		target = m.Target + " average value"
+	}
+	return fmt.Sprintf("%s (%s) at %s", name, m.Type, target)
+}
+
+// MetricsString returns every metric the HorizontalPodAutoscaler scales on
+func (h *HPAInfo) MetricsString() string {
+	var metrics []string
+	for _, m := range h.Metrics {
+		metrics = append(metrics, m.String())
+	}
+	return strings.Join(metrics, ", ")
+}
+
+// BehaviorString returns the HorizontalPodAutoscaler's scaling behavior, e.g.
+// "scaleDown: stabilizationWindowSeconds 300, 1 Pods per 60s"
+func (h *HPAInfo) BehaviorString() string {
+	if h.Behavior == nil {
+		return "default"
+	}
+	var parts []string
+	for _, rules := range []struct {
+		name  string
+		rules *autoscalingv2.HPAScalingRules
+	}{
+		{"scaleUp", h.Behavior.ScaleUp},
+		{"scaleDown", h.Behavior.ScaleDown},
+	} {
+		if rules.rules == nil {
+			continue
+		}
+		var settings []string
+		if rules.rules.StabilizationWindowSeconds != nil {
+			settings = append(settings, fmt.Sprintf("stabilizationWindowSeconds %v", *rules.rules.StabilizationWindowSeconds))
+		}
+		for _, p := range rules.rules.Policies {
+			settings = append(settings, fmt.Sprintf("%v %s per %vs", p.Value, p.Type, p.PeriodSeconds))
+		}
+		if rules.rules.SelectPolicy != nil {
+			settings = append(settings, fmt.Sprintf("selectPolicy %s", *rules.rules.SelectPolicy))
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", rules.name, strings.Join(settings, ", ")))
+	}
+	if len(parts) == 0 {
+		return "default"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// PDBInfo describes the PodDisruptionBudget matched to a workload
+type PDBInfo struct {
+	Name string `json:"name"`
+	// AvailabilityConfig holds minAvailable and maxUnavailable as effective Pod counts
+	// for the workload's replicas, with percentages rounded up
+	AvailabilityConfig map[string]int32 `json:"availabilityConfig"`
+	// MinAvailable and MaxUnavailable hold the values as configured, e.g. 1 or "50%"
+	MinAvailable   *intstr.IntOrString `json:"minAvailable,omitempty"`
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// String returns the PodDisruptionBudget's configuration along with effective Pod
+// counts for any percentages, e.g. "minAvailable: 50% (2 Pods)"
+func (p *PDBInfo) String() string {
+	var parts []string
+	for _, setting := range []struct {
+		name  string
+		value *intstr.IntOrString
+	}{
+		{"minAvailable", p.MinAvailable},
+		{"maxUnavailable", p.MaxUnavailable},
+	} {
+		if setting.value == nil {
+			continue
+		}
+		part := fmt.Sprintf("%s: %s", setting.name, setting.value.String())
+		if setting.value.Type == intstr.String {
+			part += fmt.Sprintf(" (%v Pods)", p.AvailabilityConfig[setting.name])
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 0 {
+		return "neither minAvailable nor maxUnavailable set"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// WorkloadReport holds everything that was learned about a single workload
+// along with the findings produced for it
+type WorkloadReport struct {
+	Kind      string            `json:"kind"`
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Replicas  int32             `json:"replicas"`
+	Labels    string            `json:"labels"`
+	Selectors map[string]string `json:"selectors,omitempty"`
+	HPA       *HPAInfo          `json:"hpa,omitempty"`
+	PDB       *PDBInfo          `json:"pdb,omitempty"`
+	Findings  []Finding         `json:"findings"`
+}
+
+// Report is the result of evaluating a cluster
+type Report struct {
+	ClusterName string           `json:"clusterName"`
+	Namespaces  []string         `json:"namespaces"`
+	Workloads   []WorkloadReport `json:"workloads"`
+}
+
+// Findings returns every Finding in the Report across all workloads
+func (r *Report) Findings() []Finding {
+	var findings []Finding
+	for _, w := range r.Workloads {
+		findings = append(findings, w.Findings...)
+	}
+	return findings
+}
+
+// Exceeds returns whether or not any check in the Report failed with a Severity
+// at or above threshold
+func (r *Report) Exceeds(threshold Severity) bool {
+	for _, f := range r.Findings() {
+		if !f.Passed && f.Severity.AtLeast(threshold) {
+			return true
+		}
+	}
+	return false
+}
+
+// Failed returns whether or not any check in the WorkloadReport did not pass
+func (w *WorkloadReport) Failed() bool {
+	for _, f := range w.Findings {
+		if !f.Passed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import "testing"
+
+func TestReport_Exceeds(t *testing.T) {
+	report := &Report{
+		Workloads: []WorkloadReport{
+			{
+				Findings: []Finding{
+					{RuleID: RuleHPAConfigured, Severity: SeverityWarning, Passed: false},
+					{RuleID: RuleMinReplicas, Severity: SeverityError, Passed: true},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		threshold Severity
+		want      bool
+	}{
+		{
+			name:      "A failed warning should exceed an info threshold",
+			threshold: SeverityInfo,
+			want:      true,
+		},
+		{
+			name:      "A failed warning should exceed a warning threshold",
+			threshold: SeverityWarning,
+			want:      true,
+		},
+		{
+			name:      "A passed error should not exceed an error threshold",
+			threshold: SeverityError,
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := report.Exceeds(tt.threshold); got != tt.want {
+				t.Errorf("Report.Exceeds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHPAMetric_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		metric HPAMetric
+		want   string
+	}{
+		{
+			name:   "A utilization target should be described as a percentage",
+			metric: HPAMetric{Type: "Resource", Name: "cpu", TargetType: "Utilization", Target: "80%"},
+			want:   "cpu (Resource) at 80% average utilization",
+		},
+		{
+			name:   "A ContainerResource metric should name its container",
+			metric: HPAMetric{Type: "ContainerResource", Name: "memory", Container: "app", TargetType: "AverageValue", Target: "500Mi"},
+			want:   "memory of container app (ContainerResource) at 500Mi average value",
+		},
+		{
+			name:   "A value target should be described as a value",
+			metric: HPAMetric{Type: "External", Name: "queue_depth", TargetType: "Value", Target: "30"},
+			want:   "queue_depth (External) at 30 value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.metric.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule IDs for the built-in checks carried out against each workload
+// These are stable and safe to reference from other tools
+const (
+	RuleHPAConfigured = "hpa-configured"
+	RuleHPAMetrics    = "hpa-metrics"
+	RuleHPAStatus     = "hpa-status"
+	RuleMinReplicas   = "min-replicas"
+	RulePDBConfigured = "pdb-configured"
+	RulePDBOverlap    = "pdb-overlap"
+	RulePDBDisruption = "pdb-allows-disruption"
+
+	RuleStatefulSetPodManagement  = "statefulset-pod-management"
+	RuleStatefulSetUpdateStrategy = "statefulset-update-strategy"
+
+	RuleDaemonSetUpdateStrategy = "daemonset-update-strategy"
+
+	RuleDeploymentStrategy = "deployment-strategy"
+
+	RuleReadinessProbe = "readiness-probe"
+	RuleLivenessProbe  = "liveness-probe"
+
+	RuleResourceRequests  = "resource-requests"
+	RuleMemoryLimit       = "memory-limit"
+	RuleLimitRequestRatio = "limit-request-ratio"
+
+	RuleTopologySpread = "topology-spread"
+	RulePodPlacement   = "pod-placement"
+)
+
+func init() {
+	Register(hpaConfiguredRule{})
+	Register(hpaMetricsRule{})
+	Register(hpaStatusRule{})
+	Register(minReplicasRule{})
+	Register(pdbConfiguredRule{})
+	Register(pdbOverlapRule{})
+	Register(pdbDisruptionRule{})
+	Register(statefulSetPodManagementRule{})
+	Register(statefulSetUpdateStrategyRule{})
+	Register(daemonSetUpdateStrategyRule{})
+	Register(deploymentStrategyRule{})
+	Register(readinessProbeRule{})
+	Register(livenessProbeRule{})
+	Register(resourceRequestsRule{})
+	Register(memoryLimitRule{})
+	Register(limitRequestRatioRule{})
+	Register(topologySpreadRule{})
+	Register(podPlacementRule{})
+}
+
+// hpaConfiguredRule checks that a workload has a HorizontalPodAutoscaler
+type hpaConfiguredRule struct{}
+
+func (hpaConfiguredRule) ID() string { return RuleHPAConfigured }
+
+func (hpaConfiguredRule) Description() string {
+	return "Workloads should have a HorizontalPodAutoscaler so that their replica count is not static."
+}
+
+func (hpaConfiguredRule) Severity() Severity { return SeverityWarning }
+
+func (r hpaConfiguredRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	if !w.Scalable() {
+		return nil
+	}
+	hpa, ok := s.HPA(w)
+	if !ok {
+		return nil
+	}
+	if hpa == nil {
+		return []Finding{NewFinding(r, w, false,
+			fmt.Sprintf("Could not find a HorizontalPodAutoscaler with a scaleTargetRef pointing at %s %s. Double check spec.scaleTargetRef. The %s replica count is likely static. Read more here: https://kubernetes.io/docs/tasks/run-application/horizontal-pod-autoscale/", w.Kind, w.Name, w.Kind),
+		)}
+	}
+	f := NewFinding(r, w, true,
+		fmt.Sprintf("This app has a HorizontalPodAutoscaler with %v min replicas and %v max replicas.", hpa.MinReplicas, hpa.MaxReplicas),
+	)
+	f.HPA = hpa.Name
+	return []Finding{f}
+}
+
+// minReplicasRule checks that a workload without a HorizontalPodAutoscaler runs
+// at least the minimum number of replicas
+type minReplicasRule struct{}
+
+func (minReplicasRule) ID() string { return RuleMinReplicas }
+
+func (minReplicasRule) Description() string {
+	return "Workloads should run at least the minimum number of replicas (2 by default) so that they stay up during events like rollouts and upgrades."
+}
+
+func (minReplicasRule) Severity() Severity { return SeverityError }
+
+func (r minReplicasRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	if !w.Scalable() {
+		return nil
+	}
+	// The HorizontalPodAutoscaler is in charge of replicas if there is one, and whether
+	// there is one can't be known if they couldn't be listed
+	if hpa, ok := s.HPA(w); !ok || hpa != nil {
+		return nil
+	}
+	// A workload scaled to zero has been taken down on purpose
+	if w.Replicas <= 0 {
+		return nil
+	}
+	minReplicas := s.Thresholds.MinReplicas
+	if w.Replicas < minReplicas {
+		return []Finding{NewFinding(r, w, false,
+			fmt.Sprintf("Current replica count is %v, which is less than %v. This application could be unavailable during events like rollouts and upgrades.", w.Replicas, minReplicas),
+			fmt.Sprintf("verify that the minimum replica count is not set for a single replica, enable a HorizontalPodAutoscaler, and set minReplicas to at least %v.", minReplicas),
+			"add and enable a PodDisruptionBudget with at least a maxUnavailable less than configured min replicas.",
+		)}
+	}
+	return []Finding{NewFinding(r, w, true,
+		fmt.Sprintf("Current replica count is at least %v. This helps keep this application up during events like rollouts and upgrades.", minReplicas),
+	)}
+}
+
+// pdbConfiguredRule checks that a workload has a PodDisruptionBudget
+type pdbConfiguredRule struct{}
+
+func (pdbConfiguredRule) ID() string { return RulePDBConfigured }
+
+func (pdbConfiguredRule) Description() string {
+	return "Workloads should have a PodDisruptionBudget so that voluntary disruptions don't take them down."
+}
+
+func (pdbConfiguredRule) Severity() Severity { return SeverityWarning }
+
+func (r pdbConfiguredRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	if !w.Scalable() {
+		return nil
+	}
+	pdb, ok := s.PDB(w)
+	if !ok {
+		return nil
+	}
+	if pdb == nil {
+		return []Finding{NewFinding(r, w, false,
+			"This app does not have a PodDisruptionBudget. This application could experience interruptions during rollouts, upgrades, etc. Read more here: https://kubernetes.io/docs/concepts/workloads/pods/disruptions/",
+			"enable a PodDisruptionBudget.",
+		)}
+	}
+	f := NewFinding(r, w, true,
+		fmt.Sprintf("This app has a PodDisruptionBudget configured with: %s", pdb.String()),
+	)
+	f.PDB = pdb.Name
+	return []Finding{f}
+}
+
+// pdbOverlapRule checks that a workload's Pods aren't covered by more than one
+// PodDisruptionBudget, which stops the eviction API from evicting them
+type pdbOverlapRule struct{}
+
+func (pdbOverlapRule) ID() string { return RulePDBOverlap }
+
+func (pdbOverlapRule) Description() string {
+	return "Workloads should be covered by a single PodDisruptionBudget, since Pods covered by several can't be evicted and Node drains hang."
+}
+
+func (pdbOverlapRule) Severity() Severity { return SeverityError }
+
+func (r pdbOverlapRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	if !w.Scalable() {
+		return nil
+	}
+	pdbs, ok := s.PDBs(w)
+	if !ok || len(pdbs) == 0 {
+		return nil
+	}
+	var names []string
+	for _, pdb := range pdbs {
+		names = append(names, pdb.Name)
+	}
+	if len(pdbs) == 1 {
+		f := NewFinding(r, w, true, fmt.Sprintf("This app is covered by a single PodDisruptionBudget: %s", names[0]))
+		f.PDB = names[0]
+		return []Finding{f}
+	}
+	f := NewFinding(r, w, false,
+		fmt.Sprintf("This app is covered by %v PodDisruptionBudgets: %s. The eviction API refuses to evict Pods covered by more than one PodDisruptionBudget, so Node drains during events like upgrades will hang. Read more here: https://kubernetes.io/docs/tasks/run-application/configure-pdb/", len(pdbs), strings.Join(names, ", ")),
+		fmt.Sprintf("remove all but one of the PodDisruptionBudgets (%s), or change their selectors so that they don't overlap.", strings.Join(names, ", ")),
+	)
+	f.PDB = strings.Join(names, ",")
+	return []Finding{f}
+}
+
+// pdbDisruptionRule checks that each PodDisruptionBudget covering a workload allows
+// at least one Pod to be disrupted
+type pdbDisruptionRule struct{}
+
+func (pdbDisruptionRule) ID() string { return RulePDBDisruption }
+
+func (pdbDisruptionRule) Description() string {
+	return "PodDisruptionBudgets should allow at least one Pod to be disrupted, otherwise Node drains during events like cluster upgrades never finish."
+}
+
+func (pdbDisruptionRule) Severity() Severity { return SeverityError }
+
+func (r pdbDisruptionRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	if !w.Scalable() {
+		return nil
+	}
+
+	// The fewest Pods the workload is expected to run
+	expected := w.Replicas
+	source := "replicas"
+	hpa, ok := s.HPA(w)
+	if !ok {
+		return nil
+	}
+	if hpa != nil {
+		expected = hpa.MinReplicas
+		source = "HorizontalPodAutoscaler min replicas"
+	}
+	// A workload scaled to zero has nothing to disrupt
+	if expected <= 0 {
+		return nil
+	}
+
+	var findings []Finding
+	invalid := func(pdb PDBInfo, setting string, err error) {
+		f := NewFinding(r, w, false,
+			fmt.Sprintf("Couldn't figure out the %s of PodDisruptionBudget %s: %s", setting, pdb.Name, err),
+			fmt.Sprintf("set %s to a whole number of Pods or a percentage such as 50%%.", setting),
+		)
+		f.Severity = SeverityWarning
+		f.PDB = pdb.Name
+		findings = append(findings, f)
+	}
+	pdbs, _ := s.PDBs(w)
+	for _, pdb := range pdbs {
+		var allowed int32
+		var setting string
+		switch {
+		case pdb.MaxUnavailable != nil:
+			v, err := resolvePDBValue(pdb.MaxUnavailable, expected)
+			if err != nil {
+				invalid(pdb, "maxUnavailable", err)
+				continue
+			}
+			allowed = v
+			setting = fmt.Sprintf("maxUnavailable of %s", pdb.MaxUnavailable.String())
+		case pdb.MinAvailable != nil:
+			v, err := resolvePDBValue(pdb.MinAvailable, expected)
+			if err != nil {
+				invalid(pdb, "minAvailable", err)
+				continue
+			}
+			allowed = expected - v
+			setting = fmt.Sprintf("minAvailable of %s", pdb.MinAvailable.String())
+		default:
+			// Without either set, every Pod can be disrupted
+			continue
+		}
+
+		var f Finding
+		if allowed > 0 {
+			f = NewFinding(r, w, true,
+				fmt.Sprintf("PodDisruptionBudget %s has a %s, which allows %v of %v Pods (%s) to be disrupted at once.", pdb.Name, setting, allowed, expected, source),
+			)
+		} else {
+			f = NewFinding(r, w, false,
+				fmt.Sprintf("PodDisruptionBudget %s has a %s with %v Pods (%s), so no Pods can ever be disrupted. Node drains during events like cluster upgrades will be blocked until someone steps in.", pdb.Name, setting, expected, source),
+				"set maxUnavailable to at least 1, or set minAvailable lower than the minimum replica count.",
+			)
+		}
+		f.PDB = pdb.Name
+		findings = append(findings, f)
+	}
+	return findings
+}
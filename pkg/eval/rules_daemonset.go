@@ -0,0 +1,106 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// daemonSetUpdateStrategyRule checks that a DaemonSet's rollout can't take down
+// its Pods on every Node at once
+type daemonSetUpdateStrategyRule struct{}
+
+func (daemonSetUpdateStrategyRule) ID() string { return RuleDaemonSetUpdateStrategy }
+
+func (daemonSetUpdateStrategyRule) Description() string {
+	return "DaemonSets should use the RollingUpdate updateStrategy with a maxUnavailable that keeps most Nodes running the agent during a rollout."
+}
+
+func (daemonSetUpdateStrategyRule) Severity() Severity { return SeverityWarning }
+
+func (r daemonSetUpdateStrategyRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	ds, ok := w.Object.(*appsv1.DaemonSet)
+	if !ok {
+		return nil
+	}
+	strategy := ds.Spec.UpdateStrategy
+	if strategy.Type == appsv1.OnDeleteDaemonSetStrategyType {
+		return []Finding{NewFinding(r, w, false,
+			"This DaemonSet uses the OnDelete updateStrategy. Pods are only updated when they are deleted, so Nodes end up running different versions of the agent until they are replaced. Read more here: https://kubernetes.io/docs/tasks/manage-daemon/update-daemon-set/",
+			"use the RollingUpdate updateStrategy with a small maxUnavailable.",
+		)}
+	}
+
+	// Nothing to roll out to yet
+	if w.Replicas == 0 {
+		return nil
+	}
+
+	// The defaults used by the API server if rollingUpdate isn't set
+	maxUnavailable := intstr.FromInt(1)
+	maxSurge := intstr.FromInt(0)
+	if strategy.RollingUpdate != nil {
+		if strategy.RollingUpdate.MaxUnavailable != nil {
+			maxUnavailable = *strategy.RollingUpdate.MaxUnavailable
+		}
+		if strategy.RollingUpdate.MaxSurge != nil {
+			maxSurge = *strategy.RollingUpdate.MaxSurge
+		}
+	}
+
+	// The DaemonSet controller rounds percentages up
+	unavailable, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, int(w.Replicas), true)
+	if err != nil {
+		return []Finding{NewFinding(r, w, false, fmt.Sprintf("Couldn't figure out updateStrategy.rollingUpdate.maxUnavailable: %s", err))}
+	}
+	surge, err := intstr.GetScaledValueFromIntOrPercent(&maxSurge, int(w.Replicas), true)
+	if err != nil {
+		return []Finding{NewFinding(r, w, false, fmt.Sprintf("Couldn't figure out updateStrategy.rollingUpdate.maxSurge: %s", err))}
+	}
+
+	// With a surge, the new Pod is ready before the old one is removed
+	if surge > 0 && unavailable == 0 {
+		return []Finding{NewFinding(r, w, true,
+			fmt.Sprintf("This DaemonSet surges up to %s Pods during a rollout without taking any down first.", maxSurge.String()),
+		)}
+	}
+	if unavailable >= int(w.Replicas) {
+		f := NewFinding(r, w, false,
+			fmt.Sprintf("This DaemonSet has maxUnavailable set to %s, which allows the Pods on all %v Nodes to be taken down at once during a rollout. A bad rollout will take this agent down across the whole cluster.", maxUnavailable.String(), w.Replicas),
+			"lower updateStrategy.rollingUpdate.maxUnavailable so that the rollout happens a few Nodes at a time, or use maxSurge to start new Pods before old ones are removed.",
+		)
+		f.Severity = SeverityError
+		return []Finding{f}
+	}
+	if unavailable*2 > int(w.Replicas) {
+		return []Finding{NewFinding(r, w, false,
+			fmt.Sprintf("This DaemonSet has maxUnavailable set to %s, which allows the Pods on %v of %v Nodes to be taken down at once during a rollout.", maxUnavailable.String(), unavailable, w.Replicas),
+			"lower updateStrategy.rollingUpdate.maxUnavailable so that the rollout happens a few Nodes at a time, or use maxSurge to start new Pods before old ones are removed.",
+		)}
+	}
+	return []Finding{NewFinding(r, w, true,
+		fmt.Sprintf("This DaemonSet has maxUnavailable set to %s, so at most %v of %v Nodes lose this agent at once during a rollout.", maxUnavailable.String(), unavailable, w.Replicas),
+	)}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_daemonSetUpdateStrategyRule(t *testing.T) {
+	allAtOnce := intstr.FromString("100%")
+	half := intstr.FromString("60%")
+	one := intstr.FromInt(1)
+	none := intstr.FromInt(0)
+
+	tests := []struct {
+		name         string
+		strategy     appsv1.DaemonSetUpdateStrategy
+		want         bool
+		wantSeverity Severity
+	}{
+		{
+			name:         "The default strategy should pass",
+			strategy:     appsv1.DaemonSetUpdateStrategy{},
+			want:         true,
+			wantSeverity: SeverityWarning,
+		},
+		{
+			name: "OnDelete should fail",
+			strategy: appsv1.DaemonSetUpdateStrategy{
+				Type: appsv1.OnDeleteDaemonSetStrategyType,
+			},
+			want:         false,
+			wantSeverity: SeverityWarning,
+		},
+		{
+			name: "A maxUnavailable covering every Node should fail with an error",
+			strategy: appsv1.DaemonSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: &allAtOnce},
+			},
+			want:         false,
+			wantSeverity: SeverityError,
+		},
+		{
+			name: "A maxUnavailable covering more than half the Nodes should fail",
+			strategy: appsv1.DaemonSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: &half},
+			},
+			want:         false,
+			wantSeverity: SeverityWarning,
+		},
+		{
+			name: "A surge without any unavailable Pods should pass",
+			strategy: appsv1.DaemonSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: &none, MaxSurge: &one},
+			},
+			want:         true,
+			wantSeverity: SeverityWarning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := buildDaemonSetWorkload(appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+				Spec:       appsv1.DaemonSetSpec{UpdateStrategy: tt.strategy},
+				Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 10},
+			})
+			findings := daemonSetUpdateStrategyRule{}.Evaluate(w, newClusterState(fake.NewSimpleClientset(), DefaultConfig()))
+			if len(findings) != 1 {
+				t.Fatalf("Evaluate() returned %v findings, want 1", len(findings))
+			}
+			if findings[0].Passed != tt.want || findings[0].Severity != tt.wantSeverity {
+				t.Errorf("Evaluate() = %v/%v, want %v/%v", findings[0].Passed, findings[0].Severity, tt.want, tt.wantSeverity)
+			}
+		})
+	}
+}
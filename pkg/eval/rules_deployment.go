@@ -0,0 +1,129 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// deploymentStrategyURL documents the strategies Deployments can use
+const deploymentStrategyURL = "https://kubernetes.io/docs/concepts/workloads/controllers/deployment/#strategy"
+
+// deploymentStrategyRule checks that a Deployment's rollout keeps some replicas
+// available and is able to make progress
+type deploymentStrategyRule struct{}
+
+func (deploymentStrategyRule) ID() string { return RuleDeploymentStrategy }
+
+func (deploymentStrategyRule) Description() string {
+	return "Deployments with several replicas should use the RollingUpdate strategy with a maxUnavailable that keeps some replicas up and a maxSurge that starts new Pods before old ones are removed if maxUnavailable comes to 0."
+}
+
+func (deploymentStrategyRule) Severity() Severity { return SeverityWarning }
+
+func (r deploymentStrategyRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	d, ok := w.Object.(*appsv1.Deployment)
+	if !ok {
+		return nil
+	}
+	// The HorizontalPodAutoscaler can scale down to its minimum, so that's what a rollout
+	// has to work with
+	replicas := w.Replicas
+	if hpa, _ := s.HPA(w); hpa != nil {
+		replicas = hpa.MinReplicas
+	}
+	if replicas < 2 {
+		return nil
+	}
+
+	strategy := d.Spec.Strategy
+	if strategy.Type == appsv1.RecreateDeploymentStrategyType {
+		if !servesTraffic(w) {
+			return nil
+		}
+		return []Finding{NewFinding(r, w, false,
+			fmt.Sprintf("This Deployment uses the Recreate strategy. All %v replicas are taken down before new ones are started, so every rollout is an outage. Read more here: %s", replicas, deploymentStrategyURL),
+			"use the RollingUpdate strategy unless the application can't run two versions side by side.",
+		)}
+	}
+
+	// The defaults used by the API server if rollingUpdate isn't set
+	maxUnavailable := intstr.FromString("25%")
+	maxSurge := intstr.FromString("25%")
+	if strategy.RollingUpdate != nil {
+		if strategy.RollingUpdate.MaxUnavailable != nil {
+			maxUnavailable = *strategy.RollingUpdate.MaxUnavailable
+		}
+		if strategy.RollingUpdate.MaxSurge != nil {
+			maxSurge = *strategy.RollingUpdate.MaxSurge
+		}
+	}
+
+	// The Deployment controller rounds maxSurge up and maxUnavailable down
+	unavailable, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, int(replicas), false)
+	if err != nil {
+		return []Finding{NewFinding(r, w, false, fmt.Sprintf("Couldn't figure out strategy.rollingUpdate.maxUnavailable: %s", err))}
+	}
+	surge, err := intstr.GetScaledValueFromIntOrPercent(&maxSurge, int(replicas), true)
+	if err != nil {
+		return []Finding{NewFinding(r, w, false, fmt.Sprintf("Couldn't figure out strategy.rollingUpdate.maxSurge: %s", err))}
+	}
+
+	// When both come to 0 the Deployment controller takes a replica down anyway so that
+	// the rollout can progress
+	fencepost := surge == 0 && unavailable == 0
+	if fencepost {
+		unavailable = 1
+	}
+
+	if unavailable >= int(replicas) {
+		f := NewFinding(r, w, false,
+			fmt.Sprintf("This Deployment has maxUnavailable set to %s, which allows all %v replicas to be taken down at once during a rollout. Read more here: %s", maxUnavailable.String(), replicas, deploymentStrategyURL),
+			"lower strategy.rollingUpdate.maxUnavailable so that some replicas stay up, and use maxSurge to start new Pods before old ones are removed.",
+		)
+		f.Severity = SeverityError
+		return []Finding{f}
+	}
+	if fencepost {
+		return []Finding{NewFinding(r, w, false,
+			fmt.Sprintf("This Deployment's maxSurge (%s) and maxUnavailable (%s) both come to 0 Pods for %v replicas. Since no surge is allowed, the Deployment controller takes a replica down before its replacement is ready, so every rollout runs one replica short even though maxUnavailable doesn't allow for it. Read more here: %s", maxSurge.String(), maxUnavailable.String(), replicas, deploymentStrategyURL),
+			"set strategy.rollingUpdate.maxSurge to at least 1 so that new Pods are started before old ones are removed.",
+		)}
+	}
+	return []Finding{NewFinding(r, w, true,
+		fmt.Sprintf("This Deployment rolls out with maxSurge %s and maxUnavailable %s, keeping at least %v of %v replicas up.", maxSurge.String(), maxUnavailable.String(), int(replicas)-unavailable, replicas),
+	)}
+}
+
+// servesTraffic returns whether or not the workload's Pods look like they serve requests,
+// i.e. one of their containers exposes a port
+func servesTraffic(w *Workload) bool {
+	for _, c := range w.Template.Spec.Containers {
+		if len(c.Ports) > 0 {
+			return true
+		}
+	}
+	return false
+}
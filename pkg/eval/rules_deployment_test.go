@@ -0,0 +1,141 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_deploymentStrategyRule(t *testing.T) {
+	allAtOnce := intstr.FromString("100%")
+	tenPercent := intstr.FromString("10%")
+	one := intstr.FromInt(1)
+	none := intstr.FromInt(0)
+
+	tests := []struct {
+		name         string
+		replicas     int32
+		strategy     appsv1.DeploymentStrategy
+		ports        []corev1.ContainerPort
+		want         []bool
+		wantSeverity Severity
+	}{
+		{
+			name:         "The default strategy should pass",
+			replicas:     4,
+			strategy:     appsv1.DeploymentStrategy{},
+			want:         []bool{true},
+			wantSeverity: SeverityWarning,
+		},
+		{
+			name:     "A single replica Deployment should be skipped",
+			replicas: 1,
+			strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+			ports:    []corev1.ContainerPort{{ContainerPort: 8080}},
+			want:     nil,
+		},
+		{
+			name:         "Recreate on a multi-replica Deployment serving traffic should fail",
+			replicas:     3,
+			strategy:     appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+			ports:        []corev1.ContainerPort{{ContainerPort: 8080}},
+			want:         []bool{false},
+			wantSeverity: SeverityWarning,
+		},
+		{
+			name:     "Recreate on a multi-replica Deployment without ports should be skipped",
+			replicas: 3,
+			strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+			want:     nil,
+		},
+		{
+			name:     "A maxUnavailable covering every replica should fail with an error",
+			replicas: 3,
+			strategy: appsv1.DeploymentStrategy{
+				Type:          appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{MaxUnavailable: &allAtOnce},
+			},
+			want:         []bool{false},
+			wantSeverity: SeverityError,
+		},
+		{
+			name:     "A maxSurge and maxUnavailable that both come to 0 should fail since a replica is taken down anyway",
+			replicas: 3,
+			strategy: appsv1.DeploymentStrategy{
+				Type:          appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{MaxSurge: &none, MaxUnavailable: &tenPercent},
+			},
+			want:         []bool{false},
+			wantSeverity: SeverityWarning,
+		},
+		{
+			name:     "An unavailable replica without any surge should pass",
+			replicas: 3,
+			strategy: appsv1.DeploymentStrategy{
+				Type:          appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{MaxSurge: &none, MaxUnavailable: &one},
+			},
+			want:         []bool{true},
+			wantSeverity: SeverityWarning,
+		},
+		{
+			name:     "A surge without any unavailable Pods should pass",
+			replicas: 3,
+			strategy: appsv1.DeploymentStrategy{
+				Type:          appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{MaxSurge: &one, MaxUnavailable: &none},
+			},
+			want:         []bool{true},
+			wantSeverity: SeverityWarning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: &tt.replicas,
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+					Strategy: tt.strategy,
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Ports: tt.ports}}},
+					},
+				},
+			}
+			findings := (deploymentStrategyRule{}).Evaluate(buildDeploymentWorkload(d), newClusterState(fake.NewSimpleClientset(), DefaultConfig()))
+			if len(findings) != len(tt.want) {
+				t.Fatalf("Evaluate() returned %v findings, want %v", len(findings), len(tt.want))
+			}
+			for i, f := range findings {
+				if f.Passed != tt.want[i] || f.Severity != tt.wantSeverity {
+					t.Errorf("Evaluate() = %v (%s), want %v (%s)", f.Passed, f.Severity, tt.want[i], tt.wantSeverity)
+				}
+			}
+		})
+	}
+}
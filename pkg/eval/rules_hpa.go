@@ -0,0 +1,172 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"fmt"
+	"strings"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// hpaMetricsRule checks that a HorizontalPodAutoscaler scales on at least one metric
+// it can actually compute
+type hpaMetricsRule struct{}
+
+func (hpaMetricsRule) ID() string { return RuleHPAMetrics }
+
+func (hpaMetricsRule) Description() string {
+	return "HorizontalPodAutoscalers should scale on at least one metric that can be computed. Utilization targets need resource requests on the containers they measure."
+}
+
+func (hpaMetricsRule) Severity() Severity { return SeverityWarning }
+
+func (r hpaMetricsRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	if !w.Scalable() {
+		return nil
+	}
+	hpa, _ := s.HPA(w)
+	if hpa == nil {
+		return nil
+	}
+	var missing []string
+	for _, m := range hpa.Metrics {
+		containers := missingRequests(w, m)
+		if len(containers) == 0 {
+			f := NewFinding(r, w, true,
+				fmt.Sprintf("HorizontalPodAutoscaler %s scales on %s. Scaling behavior: %s.", hpa.Name, hpa.MetricsString(), hpa.BehaviorString()),
+			)
+			f.HPA = hpa.Name
+			return []Finding{f}
+		}
+		missing = append(missing, fmt.Sprintf("%s requests on %s", m.Name, strings.Join(containers, ", ")))
+	}
+	f := NewFinding(r, w, false,
+		fmt.Sprintf("HorizontalPodAutoscaler %s scales on %s, but none of these can be computed because of missing %s. The replica count won't change with load. Read more here: https://kubernetes.io/docs/tasks/run-application/horizontal-pod-autoscale/#how-does-a-horizontalpodautoscaler-work", hpa.Name, hpa.MetricsString(), strings.Join(missing, " and ")),
+		"set resources.requests on every container measured by a utilization target, or target an average value instead.",
+	)
+	f.HPA = hpa.Name
+	return []Finding{f}
+}
+
+// hpaStatusRule checks the status a HorizontalPodAutoscaler reports to make sure it
+// is actually scaling its workload
+type hpaStatusRule struct{}
+
+func (hpaStatusRule) ID() string { return RuleHPAStatus }
+
+func (hpaStatusRule) Description() string {
+	return "HorizontalPodAutoscalers should be able to scale, fetch their metrics, and have room to add replicas."
+}
+
+func (hpaStatusRule) Severity() Severity { return SeverityWarning }
+
+func (r hpaStatusRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	if !w.Scalable() {
+		return nil
+	}
+	hpa, _ := s.HPA(w)
+	// There is nothing to go on until the controller has processed the HorizontalPodAutoscaler
+	if hpa == nil || len(hpa.Conditions) == 0 {
+		return nil
+	}
+	// The controller stops scaling a workload that has been scaled to zero on purpose
+	if c := hpa.Condition(autoscalingv2.ScalingActive); c != nil && c.Status == corev1.ConditionFalse && c.Reason == "ScalingDisabled" {
+		return nil
+	}
+
+	var findings []Finding
+	fail := func(condition autoscalingv2.HorizontalPodAutoscalerConditionType, severity Severity, message string, suggestions ...string) {
+		f := NewFinding(r, w, false, message, suggestions...)
+		f.Severity = severity
+		f.Subject = string(condition)
+		f.HPA = hpa.Name
+		findings = append(findings, f)
+	}
+	if c := hpa.Condition(autoscalingv2.AbleToScale); c != nil && c.Status == corev1.ConditionFalse {
+		fail(c.Type, SeverityError,
+			fmt.Sprintf("HorizontalPodAutoscaler %s is unable to scale %s %s (%s: %s). Read more here: https://kubernetes.io/docs/tasks/run-application/horizontal-pod-autoscale-walkthrough/#appendix-horizontal-pod-autoscaler-status-conditions", hpa.Name, w.Kind, w.Name, c.Reason, c.Message),
+			"check that spec.scaleTargetRef points at this workload and that its scale subresource can be read and updated.",
+		)
+	}
+	if c := hpa.Condition(autoscalingv2.ScalingActive); c != nil && c.Status == corev1.ConditionFalse {
+		switch {
+		case strings.Contains(c.Message, "missing request for") && hpa.CurrentReplicas <= hpa.MinReplicas:
+			fail(c.Type, SeverityError,
+				fmt.Sprintf("HorizontalPodAutoscaler %s is stuck at its minimum of %v replicas because utilization can't be computed without resource requests (%s: %s).", hpa.Name, hpa.MinReplicas, c.Reason, c.Message),
+				"set resources.requests on every container measured by a utilization target.",
+			)
+		case strings.Contains(c.Message, "missing request for"):
+			fail(c.Type, SeverityError,
+				fmt.Sprintf("HorizontalPodAutoscaler %s can't compute utilization without resource requests and won't scale from %v replicas (%s: %s).", hpa.Name, hpa.CurrentReplicas, c.Reason, c.Message),
+				"set resources.requests on every container measured by a utilization target.",
+			)
+		case strings.HasPrefix(c.Reason, "FailedGet") && strings.HasSuffix(c.Reason, "Metric"):
+			fail(c.Type, SeverityError,
+				fmt.Sprintf("HorizontalPodAutoscaler %s is unable to fetch its metrics and won't scale from %v replicas (%s: %s).", hpa.Name, hpa.CurrentReplicas, c.Reason, c.Message),
+				"check that the metrics server, or the adapter serving custom and external metrics, is running, e.g. using kubectl get apiservices.",
+			)
+		default:
+			fail(c.Type, SeverityError,
+				fmt.Sprintf("HorizontalPodAutoscaler %s isn't scaling and won't change from %v replicas (%s: %s).", hpa.Name, hpa.CurrentReplicas, c.Reason, c.Message),
+				"check the HorizontalPodAutoscaler's events using kubectl describe hpa.",
+			)
+		}
+	}
+	if c := hpa.Condition(autoscalingv2.ScalingLimited); c != nil && c.Status == corev1.ConditionTrue && c.Reason == "TooManyReplicas" {
+		fail(c.Type, SeverityWarning,
+			fmt.Sprintf("HorizontalPodAutoscaler %s is pinned at its maximum of %v replicas and wants more. This application may not keep up with load.", hpa.Name, hpa.MaxReplicas),
+			"raise maxReplicas, or check whether the metric target is too low.",
+		)
+	}
+	if len(findings) > 0 {
+		return findings
+	}
+	f := NewFinding(r, w, true,
+		fmt.Sprintf("HorizontalPodAutoscaler %s is running %v replicas and wants %v. It is able to scale and fetch its metrics.", hpa.Name, hpa.CurrentReplicas, hpa.DesiredReplicas),
+	)
+	f.HPA = hpa.Name
+	return []Finding{f}
+}
+
+// missingRequests returns the containers a HorizontalPodAutoscaler metric can't be
+// computed for because they lack a resource request
+// Only Utilization targets on Resource and ContainerResource metrics need requests
+func missingRequests(w *Workload, m HPAMetric) []string {
+	if m.TargetType != string(autoscalingv2.UtilizationMetricType) {
+		return nil
+	}
+	if m.Type != string(autoscalingv2.ResourceMetricSourceType) && m.Type != string(autoscalingv2.ContainerResourceMetricSourceType) {
+		return nil
+	}
+	var containers []string
+	for _, c := range w.Template.Spec.Containers {
+		if m.Container != "" && c.Name != m.Container {
+			continue
+		}
+		if _, ok := effectiveRequest(c, corev1.ResourceName(m.Name)); !ok {
+			containers = append(containers, c.Name)
+		}
+	}
+	return containers
+}
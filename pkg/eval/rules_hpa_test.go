@@ -0,0 +1,244 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// hpaClusterState returns a ClusterState for a cluster serving autoscaling/v2
+func hpaClusterState(objects ...runtime.Object) *ClusterState {
+	clientset := fake.NewSimpleClientset(objects...)
+	clientset.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "autoscaling/v2", APIResources: []metav1.APIResource{{Name: "horizontalpodautoscalers"}}},
+	}
+	return newClusterState(clientset, DefaultConfig())
+}
+
+func Test_hpaMetricsRule(t *testing.T) {
+	queueDepth := resource.MustParse("30")
+	external := autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ExternalMetricSourceType,
+		External: &autoscalingv2.ExternalMetricSource{
+			Metric: autoscalingv2.MetricIdentifier{Name: "queue_depth"},
+			Target: autoscalingv2.MetricTarget{Type: autoscalingv2.ValueMetricType, Value: &queueDepth},
+		},
+	}
+	hpa := func(metrics ...autoscalingv2.MetricSpec) runtime.Object {
+		return &autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", APIVersion: "apps/v1", Name: "foo"},
+				MaxReplicas:    5,
+				Metrics:        metrics,
+			},
+		}
+	}
+	requests := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}
+
+	tests := []struct {
+		name     string
+		requests corev1.ResourceList
+		objects  []runtime.Object
+		want     []bool
+	}{
+		{
+			name:    "A workload without a HorizontalPodAutoscaler should be skipped",
+			objects: nil,
+			want:    nil,
+		},
+		{
+			name:     "A cpu utilization target with cpu requests should pass",
+			requests: requests,
+			objects:  []runtime.Object{hpa(cpuUtilizationMetric(80))},
+			want:     []bool{true},
+		},
+		{
+			name:    "A cpu utilization target without cpu requests should fail",
+			objects: []runtime.Object{hpa(cpuUtilizationMetric(80))},
+			want:    []bool{false},
+		},
+		{
+			name:    "The default cpu utilization target without cpu requests should fail",
+			objects: []runtime.Object{hpa()},
+			want:    []bool{false},
+		},
+		{
+			name:    "An external metric should pass without requests",
+			objects: []runtime.Object{hpa(cpuUtilizationMetric(80), external)},
+			want:    []bool{true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testWorkload(2)
+			w.Template.Spec.Containers = []corev1.Container{
+				{Name: "app", Resources: corev1.ResourceRequirements{Requests: tt.requests}},
+			}
+			var got []bool
+			for _, f := range (hpaMetricsRule{}).Evaluate(w, hpaClusterState(tt.objects...)) {
+				got = append(got, f.Passed)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_hpaStatusRule(t *testing.T) {
+	var minReplicas int32 = 2
+	condition := func(conditionType autoscalingv2.HorizontalPodAutoscalerConditionType, status corev1.ConditionStatus, reason, message string) autoscalingv2.HorizontalPodAutoscalerCondition {
+		return autoscalingv2.HorizontalPodAutoscalerCondition{Type: conditionType, Status: status, Reason: reason, Message: message}
+	}
+	healthy := []autoscalingv2.HorizontalPodAutoscalerCondition{
+		condition(autoscalingv2.AbleToScale, corev1.ConditionTrue, "ReadyForNewScale", ""),
+		condition(autoscalingv2.ScalingActive, corev1.ConditionTrue, "ValidMetricFound", ""),
+		condition(autoscalingv2.ScalingLimited, corev1.ConditionFalse, "DesiredWithinRange", ""),
+	}
+	hpa := func(current, desired int32, conditions ...autoscalingv2.HorizontalPodAutoscalerCondition) runtime.Object {
+		return &autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", APIVersion: "apps/v1", Name: "foo"},
+				MinReplicas:    &minReplicas,
+				MaxReplicas:    5,
+			},
+			Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+				CurrentReplicas: current,
+				DesiredReplicas: desired,
+				Conditions:      conditions,
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		objects      []runtime.Object
+		want         []bool
+		wantSeverity []Severity
+		wantMessage  string
+	}{
+		{
+			name:    "A HorizontalPodAutoscaler without any conditions should be skipped",
+			objects: []runtime.Object{hpa(0, 0)},
+			want:    nil,
+		},
+		{
+			name:         "A HorizontalPodAutoscaler able to scale within range should pass",
+			objects:      []runtime.Object{hpa(3, 3, healthy...)},
+			want:         []bool{true},
+			wantSeverity: []Severity{SeverityWarning},
+		},
+		{
+			name: "A HorizontalPodAutoscaler unable to scale should fail with an error",
+			objects: []runtime.Object{hpa(3, 3,
+				condition(autoscalingv2.AbleToScale, corev1.ConditionFalse, "FailedGetScale", "deployments/scale.apps \"foo\" not found"),
+			)},
+			want:         []bool{false},
+			wantSeverity: []Severity{SeverityError},
+		},
+		{
+			name: "A HorizontalPodAutoscaler unable to fetch metrics should fail with an error",
+			objects: []runtime.Object{hpa(3, 3,
+				condition(autoscalingv2.ScalingActive, corev1.ConditionFalse, "FailedGetExternalMetric", "unable to get external metric default/queue_depth"),
+			)},
+			want:         []bool{false},
+			wantSeverity: []Severity{SeverityError},
+			wantMessage:  "unable to fetch its metrics",
+		},
+		{
+			name: "A HorizontalPodAutoscaler unable to fetch Pods metrics should fail with an error",
+			objects: []runtime.Object{hpa(3, 3,
+				condition(autoscalingv2.ScalingActive, corev1.ConditionFalse, "FailedGetPodsMetric", "unable to get metric http_requests"),
+			)},
+			want:         []bool{false},
+			wantSeverity: []Severity{SeverityError},
+			wantMessage:  "unable to fetch its metrics",
+		},
+		{
+			name: "A HorizontalPodAutoscaler whose target is scaled to zero should be skipped",
+			objects: []runtime.Object{hpa(0, 0,
+				condition(autoscalingv2.AbleToScale, corev1.ConditionTrue, "SucceededGetScale", ""),
+				condition(autoscalingv2.ScalingActive, corev1.ConditionFalse, "ScalingDisabled", "scaling is disabled since the replica count of the target is zero"),
+			)},
+			want: nil,
+		},
+		{
+			name: "A HorizontalPodAutoscaler inactive for another reason should fail without blaming metrics",
+			objects: []runtime.Object{hpa(3, 3,
+				condition(autoscalingv2.ScalingActive, corev1.ConditionFalse, "InvalidSelector", "couldn't convert selector into a corresponding internal selector object"),
+			)},
+			want:         []bool{false},
+			wantSeverity: []Severity{SeverityError},
+			wantMessage:  "isn't scaling",
+		},
+		{
+			name: "A HorizontalPodAutoscaler stuck at min because of missing requests should fail with an error",
+			objects: []runtime.Object{hpa(2, 2,
+				condition(autoscalingv2.ScalingActive, corev1.ConditionFalse, "FailedGetResourceMetric", "failed to get cpu utilization: missing request for cpu"),
+			)},
+			want:         []bool{false},
+			wantSeverity: []Severity{SeverityError},
+		},
+		{
+			name: "A HorizontalPodAutoscaler pinned at max should fail",
+			objects: []runtime.Object{hpa(5, 5,
+				condition(autoscalingv2.AbleToScale, corev1.ConditionTrue, "ReadyForNewScale", ""),
+				condition(autoscalingv2.ScalingActive, corev1.ConditionTrue, "ValidMetricFound", ""),
+				condition(autoscalingv2.ScalingLimited, corev1.ConditionTrue, "TooManyReplicas", "the desired replica count is more than the maximum replica count"),
+			)},
+			want:         []bool{false},
+			wantSeverity: []Severity{SeverityWarning},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []bool
+			var gotSeverity []Severity
+			var gotMessage string
+			for _, f := range (hpaStatusRule{}).Evaluate(testWorkload(2), hpaClusterState(tt.objects...)) {
+				got = append(got, f.Passed)
+				gotSeverity = append(gotSeverity, f.Severity)
+				gotMessage += f.Message
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotSeverity, tt.wantSeverity) {
+				t.Errorf("Evaluate() severity = %v, want %v", gotSeverity, tt.wantSeverity)
+			}
+			if !strings.Contains(gotMessage, tt.wantMessage) {
+				t.Errorf("Evaluate() message = %q, want it to contain %q", gotMessage, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"fmt"
+	"sort"
+)
+
+// podPlacementRule checks where a workload's ready Pods are actually running to find
+// replicas that share a single Node or zone
+type podPlacementRule struct{}
+
+func (podPlacementRule) ID() string { return RulePodPlacement }
+
+func (podPlacementRule) Description() string {
+	return "The ready replicas of a workload should not all be running on a single Node or in a single zone."
+}
+
+func (podPlacementRule) Severity() Severity { return SeverityWarning }
+
+func (r podPlacementRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	if !w.Scalable() {
+		return nil
+	}
+	placements, ok := s.Placement(w)
+	// A single ready replica is already covered by the replica checks
+	if !ok || len(placements) < 2 {
+		return nil
+	}
+	nodes := distinct(placements, func(p PodPlacement) string { return p.Node })
+	zones := distinct(placements, func(p PodPlacement) string { return p.Zone })
+
+	if len(nodes) == 1 {
+		f := NewFinding(r, w, false,
+			fmt.Sprintf("Single point of failure: all %v ready replicas are running on Node %s. Losing that Node takes this application down. Read more here: https://kubernetes.io/docs/concepts/scheduling-eviction/topology-spread-constraints/", len(placements), nodes[0]),
+			"add a topologySpreadConstraint or pod anti-affinity across kubernetes.io/hostname, then restart the Pods so that they are rescheduled.",
+		)
+		f.Severity = SeverityError
+		return []Finding{f}
+	}
+	// Zones can only be compared if the Nodes are labelled and there is more than one
+	if len(zones) == 1 && zones[0] != "" && s.Zones() > 1 {
+		return []Finding{NewFinding(r, w, false,
+			fmt.Sprintf("Single point of failure: all %v ready replicas are running in zone %s across %v Nodes. A zone outage takes this application down.", len(placements), zones[0], len(nodes)),
+			"add a topologySpreadConstraint or pod anti-affinity across topology.kubernetes.io/zone, then restart the Pods so that they are rescheduled.",
+		)}
+	}
+	return []Finding{NewFinding(r, w, true,
+		fmt.Sprintf("%v ready replicas are running across %v Nodes.", len(placements), len(nodes)),
+	)}
+}
+
+// distinct returns the sorted, distinct values of key across placements
+func distinct(placements []PodPlacement, key func(PodPlacement) string) []string {
+	seen := map[string]bool{}
+	var values []string
+	for _, p := range placements {
+		if v := key(p); !seen[v] {
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
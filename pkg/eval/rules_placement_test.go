@@ -0,0 +1,132 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// testNode returns a Node in the given zone
+func testNode(name, zone string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{corev1.LabelHostname: name, corev1.LabelTopologyZone: zone},
+		},
+	}
+}
+
+// testPod returns a Pod labelled app=foo running on the given Node
+func testPod(name, node string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "foo"}},
+		Spec:       corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func Test_podPlacementRule(t *testing.T) {
+	nodes := []runtime.Object{testNode("a1", "a"), testNode("a2", "a"), testNode("b1", "b")}
+
+	tests := []struct {
+		name           string
+		objects        []runtime.Object
+		nodesForbidden bool
+		want           []bool
+		wantSeverity   []Severity
+	}{
+		{
+			name:    "A single ready replica should be skipped",
+			objects: append([]runtime.Object{testPod("foo-1", "a1", true), testPod("foo-2", "a2", false)}, nodes...),
+			want:    nil,
+		},
+		{
+			name:         "Ready replicas on a single Node should fail with an error",
+			objects:      append([]runtime.Object{testPod("foo-1", "a1", true), testPod("foo-2", "a1", true), testPod("foo-3", "b1", false)}, nodes...),
+			want:         []bool{false},
+			wantSeverity: []Severity{SeverityError},
+		},
+		{
+			name:         "Ready replicas in a single zone should fail",
+			objects:      append([]runtime.Object{testPod("foo-1", "a1", true), testPod("foo-2", "a2", true)}, nodes...),
+			want:         []bool{false},
+			wantSeverity: []Severity{SeverityWarning},
+		},
+		{
+			name:         "Ready replicas across zones should pass",
+			objects:      append([]runtime.Object{testPod("foo-1", "a1", true), testPod("foo-2", "b1", true)}, nodes...),
+			want:         []bool{true},
+			wantSeverity: []Severity{SeverityWarning},
+		},
+		{
+			name:         "Ready replicas in a single zone should pass when the cluster only has one zone",
+			objects:      []runtime.Object{testPod("foo-1", "a1", true), testPod("foo-2", "a2", true), testNode("a1", "a"), testNode("a2", "a")},
+			want:         []bool{true},
+			wantSeverity: []Severity{SeverityWarning},
+		},
+		{
+			name:           "The check should be skipped when Nodes can't be listed",
+			objects:        append([]runtime.Object{testPod("foo-1", "a1", true), testPod("foo-2", "a1", true)}, nodes...),
+			nodesForbidden: true,
+			want:           nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(tt.objects...)
+			if tt.nodesForbidden {
+				clientset.PrependReactor("list", "nodes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, errors.NewForbidden(schema.GroupResource{Resource: "nodes"}, "", fmt.Errorf("cannot list resource \"nodes\" at the cluster scope"))
+				})
+			}
+			var got []bool
+			var gotSeverity []Severity
+			for _, f := range (podPlacementRule{}).Evaluate(testWorkload(3), newClusterState(clientset, DefaultConfig())) {
+				got = append(got, f.Passed)
+				gotSeverity = append(gotSeverity, f.Severity)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotSeverity, tt.wantSeverity) {
+				t.Errorf("Evaluate() severity = %v, want %v", gotSeverity, tt.wantSeverity)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// probesURL documents liveness, readiness and startup probes
+const probesURL = "https://kubernetes.io/docs/tasks/configure-pod-container/configure-liveness-readiness-startup-probes/"
+
+// newContainerFinding returns a Finding about a single container in the workload's Pod template
+func newContainerFinding(r Rule, w *Workload, c corev1.Container, passed bool, message string, suggestions ...string) Finding {
+	f := NewFinding(r, w, passed, message, suggestions...)
+	f.Container = c.Name
+	return f
+}
+
+// readinessProbeRule checks that every container has a readiness probe
+type readinessProbeRule struct{}
+
+func (readinessProbeRule) ID() string { return RuleReadinessProbe }
+
+func (readinessProbeRule) Description() string {
+	return "Containers should have a readiness probe so that Pods aren't sent traffic before they are ready, e.g. during rollouts."
+}
+
+func (readinessProbeRule) Severity() Severity { return SeverityWarning }
+
+func (r readinessProbeRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	var findings []Finding
+	for _, c := range w.Template.Spec.Containers {
+		if c.ReadinessProbe == nil {
+			findings = append(findings, newContainerFinding(r, w, c, false,
+				fmt.Sprintf("Container %s does not have a readiness probe. Its Pods are sent traffic as soon as they start, including while a rollout replaces them. Read more here: %s", c.Name, probesURL),
+				fmt.Sprintf("add a readinessProbe to container %s that only succeeds once it can serve requests.", c.Name),
+			))
+			continue
+		}
+		findings = append(findings, newContainerFinding(r, w, c, true,
+			fmt.Sprintf("Container %s has a readiness probe.", c.Name),
+		))
+	}
+	return findings
+}
+
+// livenessProbeRule checks that every container has a liveness probe that won't restart
+// it needlessly
+type livenessProbeRule struct{}
+
+func (livenessProbeRule) ID() string { return RuleLivenessProbe }
+
+func (livenessProbeRule) Description() string {
+	return "Containers should have a liveness probe that tolerates brief failures and checks something different from their readiness probe."
+}
+
+func (livenessProbeRule) Severity() Severity { return SeverityWarning }
+
+func (r livenessProbeRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	var findings []Finding
+	for _, c := range w.Template.Spec.Containers {
+		probe := c.LivenessProbe
+		if probe == nil {
+			f := newContainerFinding(r, w, c, false,
+				fmt.Sprintf("Container %s does not have a liveness probe. If it deadlocks it won't be restarted. Read more here: %s", c.Name, probesURL),
+				fmt.Sprintf("add a livenessProbe to container %s that fails only when it can't recover on its own.", c.Name),
+			)
+			f.Severity = SeverityInfo
+			findings = append(findings, f)
+			continue
+		}
+
+		// periodSeconds and failureThreshold default to 10 and 3
+		periodSeconds, failureThreshold := probe.PeriodSeconds, probe.FailureThreshold
+		if periodSeconds == 0 {
+			periodSeconds = 10
+		}
+		if failureThreshold == 0 {
+			failureThreshold = 3
+		}
+		failing := len(findings)
+		if window := periodSeconds * failureThreshold; failureThreshold == 1 || window < s.Thresholds.MinLivenessFailureSeconds {
+			f := newContainerFinding(r, w, c, false,
+				fmt.Sprintf("Container %s has an aggressive liveness probe that restarts it after failing %v time(s) in %vs. Brief slowdowns, e.g. garbage collection or a busy Node, will cause restarts that make things worse. Read more here: %s", c.Name, failureThreshold, window, probesURL),
+				fmt.Sprintf("raise failureThreshold or periodSeconds on the livenessProbe of container %s so that it tolerates at least %vs of failures.", c.Name, s.Thresholds.MinLivenessFailureSeconds),
+				"if the container is slow to start, add a startupProbe rather than shortening the livenessProbe.",
+			)
+			f.Subject = "failureThreshold"
+			findings = append(findings, f)
+		}
+		if reflect.DeepEqual(probe, c.ReadinessProbe) {
+			f := newContainerFinding(r, w, c, false,
+				fmt.Sprintf("Container %s has a liveness probe identical to its readiness probe. A container that is only overloaded will be restarted instead of being taken out of rotation until it catches up. Read more here: %s", c.Name, probesURL),
+				fmt.Sprintf("have the livenessProbe of container %s check that the process is alive rather than whether it can serve requests, or give it a higher failureThreshold.", c.Name),
+			)
+			f.Subject = "readinessProbe"
+			findings = append(findings, f)
+		}
+		if len(findings) == failing {
+			findings = append(findings, newContainerFinding(r, w, c, true,
+				fmt.Sprintf("Container %s has a liveness probe that restarts it after failing for %vs.", c.Name, periodSeconds*failureThreshold),
+			))
+		}
+	}
+	return findings
+}
@@ -0,0 +1,126 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// testProbe returns an HTTP probe against path on port 8080
+func testProbe(path string, periodSeconds, failureThreshold int32) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{Path: path, Port: intstr.FromInt(8080)},
+		},
+		PeriodSeconds:    periodSeconds,
+		FailureThreshold: failureThreshold,
+	}
+}
+
+func Test_readinessProbeRule(t *testing.T) {
+	w := testWorkload(2)
+	w.Template.Spec.Containers = []corev1.Container{
+		{Name: "app", ReadinessProbe: testProbe("/ready", 0, 0)},
+		{Name: "sidecar"},
+	}
+	want := []bool{true, false}
+	if got := evaluateRule(readinessProbeRule{}, w); !reflect.DeepEqual(got, want) {
+		t.Errorf("Evaluate() = %v, want %v", got, want)
+	}
+}
+
+func Test_livenessProbeRule(t *testing.T) {
+	tests := []struct {
+		name         string
+		container    corev1.Container
+		want         []bool
+		wantSeverity []Severity
+	}{
+		{
+			name:         "A container without a liveness probe should fail as info",
+			container:    corev1.Container{Name: "app"},
+			want:         []bool{false},
+			wantSeverity: []Severity{SeverityInfo},
+		},
+		{
+			name: "A liveness probe using the defaults should pass",
+			container: corev1.Container{
+				Name:           "app",
+				LivenessProbe:  testProbe("/healthz", 0, 0),
+				ReadinessProbe: testProbe("/ready", 0, 0),
+			},
+			want:         []bool{true},
+			wantSeverity: []Severity{SeverityWarning},
+		},
+		{
+			name: "A liveness probe restarting the container after a single failure should fail",
+			container: corev1.Container{
+				Name:          "app",
+				LivenessProbe: testProbe("/healthz", 30, 1),
+			},
+			want:         []bool{false},
+			wantSeverity: []Severity{SeverityWarning},
+		},
+		{
+			name: "A liveness probe restarting the container within the threshold should fail",
+			container: corev1.Container{
+				Name:          "app",
+				LivenessProbe: testProbe("/healthz", 2, 3),
+			},
+			want:         []bool{false},
+			wantSeverity: []Severity{SeverityWarning},
+		},
+		{
+			name: "A liveness probe identical to the readiness probe should fail",
+			container: corev1.Container{
+				Name:           "app",
+				LivenessProbe:  testProbe("/healthz", 0, 0),
+				ReadinessProbe: testProbe("/healthz", 0, 0),
+			},
+			want:         []bool{false},
+			wantSeverity: []Severity{SeverityWarning},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testWorkload(2)
+			w.Template.Spec.Containers = []corev1.Container{tt.container}
+			s := newClusterState(nil, DefaultConfig())
+			var got []bool
+			var gotSeverity []Severity
+			for _, f := range (livenessProbeRule{}).Evaluate(w, s) {
+				got = append(got, f.Passed)
+				gotSeverity = append(gotSeverity, f.Severity)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotSeverity, tt.wantSeverity) {
+				t.Errorf("Evaluate() severity = %v, want %v", gotSeverity, tt.wantSeverity)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"fmt"
+	"strings"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// resourcesURL documents resource requests and limits
+const resourcesURL = "https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/"
+
+// effectiveRequest returns a container's request for a resource
+// Kubernetes uses the limit as the request when only a limit is set
+func effectiveRequest(c corev1.Container, name corev1.ResourceName) (resource.Quantity, bool) {
+	if q, ok := c.Resources.Requests[name]; ok {
+		return q, true
+	}
+	q, ok := c.Resources.Limits[name]
+	return q, ok
+}
+
+// resourceRequestsRule checks that every container requests cpu and memory
+type resourceRequestsRule struct{}
+
+func (resourceRequestsRule) ID() string { return RuleResourceRequests }
+
+func (resourceRequestsRule) Description() string {
+	return "Containers should request cpu and memory so that they are scheduled onto Nodes with room for them and can be autoscaled on utilization."
+}
+
+func (resourceRequestsRule) Severity() Severity { return SeverityWarning }
+
+func (r resourceRequestsRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	var hpa *HPAInfo
+	if w.Scalable() {
+		hpa, _ = s.HPA(w)
+	}
+	var findings []Finding
+	for _, c := range w.Template.Spec.Containers {
+		var missing []string
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			if _, ok := effectiveRequest(c, name); !ok {
+				missing = append(missing, string(name))
+			}
+		}
+		if len(missing) == 0 {
+			findings = append(findings, newContainerFinding(r, w, c, true,
+				fmt.Sprintf("Container %s requests cpu and memory.", c.Name),
+			))
+			continue
+		}
+
+		message := fmt.Sprintf("Container %s does not request %s. It can be scheduled onto Nodes without room for it and is among the first to be evicted when a Node runs short.", c.Name, strings.Join(missing, " or "))
+		var broken []string
+		if hpa != nil {
+			for _, m := range hpa.Metrics {
+				if m.TargetType == string(autoscalingv2.UtilizationMetricType) && (m.Container == "" || m.Container == c.Name) && contains(missing, m.Name) {
+					broken = append(broken, m.Name)
+				}
+			}
+		}
+		if len(broken) > 0 {
+			message += fmt.Sprintf(" HorizontalPodAutoscaler %s can't compute %s utilization without it.", hpa.Name, strings.Join(broken, " or "))
+		}
+		f := newContainerFinding(r, w, c, false,
+			message+" Read more here: "+resourcesURL,
+			fmt.Sprintf("set resources.requests.%s on container %s based on its usage.", strings.Join(missing, " and resources.requests."), c.Name),
+		)
+		if len(broken) > 0 {
+			f.Severity = SeverityError
+			f.HPA = hpa.Name
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// memoryLimitRule checks that every container has a memory limit
+type memoryLimitRule struct{}
+
+func (memoryLimitRule) ID() string { return RuleMemoryLimit }
+
+func (memoryLimitRule) Description() string {
+	return "Containers should have a memory limit so that a leak can't take memory from everything else on the Node."
+}
+
+func (memoryLimitRule) Severity() Severity { return SeverityWarning }
+
+func (r memoryLimitRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	var findings []Finding
+	for _, c := range w.Template.Spec.Containers {
+		if _, ok := c.Resources.Limits[corev1.ResourceMemory]; !ok {
+			findings = append(findings, newContainerFinding(r, w, c, false,
+				fmt.Sprintf("Container %s does not have a memory limit. If its memory use grows unchecked, the Node runs short and other Pods are evicted along with it. Read more here: %s", c.Name, resourcesURL),
+				fmt.Sprintf("set resources.limits.memory on container %s.", c.Name),
+			))
+			continue
+		}
+		findings = append(findings, newContainerFinding(r, w, c, true,
+			fmt.Sprintf("Container %s has a memory limit.", c.Name),
+		))
+	}
+	return findings
+}
+
+// limitRequestRatioRule checks that no container's limits are far above its requests
+// The API rejects requests above limits, so it's limits far above requests that
+// overcommit Nodes
+type limitRequestRatioRule struct{}
+
+func (limitRequestRatioRule) ID() string { return RuleLimitRequestRatio }
+
+func (limitRequestRatioRule) Description() string {
+	return "Container limits should stay within thresholds.maxLimitRequestRatio of their requests so that Nodes aren't overcommitted."
+}
+
+func (limitRequestRatioRule) Severity() Severity { return SeverityWarning }
+
+func (r limitRequestRatioRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	maxRatio := s.Thresholds.MaxLimitRequestRatio
+	var findings []Finding
+	for _, c := range w.Template.Spec.Containers {
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			limit, hasLimit := c.Resources.Limits[name]
+			request, hasRequest := c.Resources.Requests[name]
+			if !hasLimit || !hasRequest || request.IsZero() {
+				continue
+			}
+			ratio := float64(limit.MilliValue()) / float64(request.MilliValue())
+			var f Finding
+			if ratio <= maxRatio {
+				f = newContainerFinding(r, w, c, true,
+					fmt.Sprintf("Container %s has a %s limit within %v times its request.", c.Name, name, maxRatio),
+				)
+			} else {
+				f = newContainerFinding(r, w, c, false,
+					fmt.Sprintf("Container %s has a %s limit of %s, %.1f times its request of %s. The scheduler only sets aside the request, so Nodes can be overcommitted and Pods evicted or throttled when several containers use more than they request. Read more here: %s", c.Name, name, limit.String(), ratio, request.String(), resourcesURL),
+					fmt.Sprintf("raise resources.requests.%s on container %s closer to its actual usage, or lower its limit to within %v times the request.", name, c.Name, maxRatio),
+				)
+			}
+			f.Subject = string(name)
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// contains returns whether or not values contains value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
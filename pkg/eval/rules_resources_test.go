@@ -0,0 +1,164 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"reflect"
+	"testing"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// testResources returns ResourceRequirements from cpu and memory quantities, leaving out empty ones
+func testResources(cpuRequest, memoryRequest, cpuLimit, memoryLimit string) corev1.ResourceRequirements {
+	list := func(cpu, memory string) corev1.ResourceList {
+		l := corev1.ResourceList{}
+		if cpu != "" {
+			l[corev1.ResourceCPU] = resource.MustParse(cpu)
+		}
+		if memory != "" {
+			l[corev1.ResourceMemory] = resource.MustParse(memory)
+		}
+		return l
+	}
+	return corev1.ResourceRequirements{
+		Requests: list(cpuRequest, memoryRequest),
+		Limits:   list(cpuLimit, memoryLimit),
+	}
+}
+
+func Test_resourceRequestsRule(t *testing.T) {
+	cpuHPA := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", APIVersion: "apps/v1", Name: "foo"},
+			MaxReplicas:    5,
+			Metrics:        []autoscalingv2.MetricSpec{cpuUtilizationMetric(80)},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		resources    corev1.ResourceRequirements
+		objects      []runtime.Object
+		want         []bool
+		wantSeverity []Severity
+	}{
+		{
+			name:         "A container requesting cpu and memory should pass",
+			resources:    testResources("100m", "128Mi", "", ""),
+			want:         []bool{true},
+			wantSeverity: []Severity{SeverityWarning},
+		},
+		{
+			name:         "A container with only limits should pass since they become its requests",
+			resources:    testResources("", "", "100m", "128Mi"),
+			want:         []bool{true},
+			wantSeverity: []Severity{SeverityWarning},
+		},
+		{
+			name:         "A container without a memory request should fail",
+			resources:    testResources("100m", "", "", ""),
+			want:         []bool{false},
+			wantSeverity: []Severity{SeverityWarning},
+		},
+		{
+			name:         "A container without a cpu request scaled on cpu utilization should fail with an error",
+			resources:    testResources("", "128Mi", "", ""),
+			objects:      []runtime.Object{cpuHPA},
+			want:         []bool{false},
+			wantSeverity: []Severity{SeverityError},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testWorkload(2)
+			w.Template.Spec.Containers = []corev1.Container{{Name: "app", Resources: tt.resources}}
+			var got []bool
+			var gotSeverity []Severity
+			for _, f := range (resourceRequestsRule{}).Evaluate(w, hpaClusterState(tt.objects...)) {
+				got = append(got, f.Passed)
+				gotSeverity = append(gotSeverity, f.Severity)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotSeverity, tt.wantSeverity) {
+				t.Errorf("Evaluate() severity = %v, want %v", gotSeverity, tt.wantSeverity)
+			}
+		})
+	}
+}
+
+func Test_memoryLimitRule(t *testing.T) {
+	w := testWorkload(2)
+	w.Template.Spec.Containers = []corev1.Container{
+		{Name: "app", Resources: testResources("", "128Mi", "", "256Mi")},
+		{Name: "sidecar", Resources: testResources("", "128Mi", "", "")},
+	}
+	want := []bool{true, false}
+	if got := evaluateRule(memoryLimitRule{}, w); !reflect.DeepEqual(got, want) {
+		t.Errorf("Evaluate() = %v, want %v", got, want)
+	}
+}
+
+func Test_limitRequestRatioRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources corev1.ResourceRequirements
+		want      []bool
+	}{
+		{
+			name:      "A container without limits should be skipped",
+			resources: testResources("100m", "128Mi", "", ""),
+			want:      nil,
+		},
+		{
+			name:      "A container with limits within the ratio should pass for cpu and memory",
+			resources: testResources("100m", "128Mi", "400m", "512Mi"),
+			want:      []bool{true, true},
+		},
+		{
+			name:      "A container with a memory limit far above its request should fail for memory",
+			resources: testResources("100m", "128Mi", "200m", "1Gi"),
+			want:      []bool{true, false},
+		},
+		{
+			name:      "A container with cpu and memory limits far above their requests should fail twice",
+			resources: testResources("100m", "128Mi", "2", "1Gi"),
+			want:      []bool{false, false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testWorkload(2)
+			w.Template.Spec.Containers = []corev1.Container{{Name: "app", Resources: tt.resources}}
+			if got := evaluateRule(limitRequestRatioRule{}, w); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
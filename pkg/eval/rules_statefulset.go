@@ -0,0 +1,102 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// statefulSetPodManagementRule checks that multi-replica StatefulSets don't needlessly
+// start and stop their Pods one at a time
+type statefulSetPodManagementRule struct{}
+
+func (statefulSetPodManagementRule) ID() string { return RuleStatefulSetPodManagement }
+
+func (statefulSetPodManagementRule) Description() string {
+	return "StatefulSets whose Pods don't depend on each other should use the Parallel podManagementPolicy so that they recover quickly."
+}
+
+func (statefulSetPodManagementRule) Severity() Severity { return SeverityInfo }
+
+func (r statefulSetPodManagementRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	ss, ok := w.Object.(*appsv1.StatefulSet)
+	if !ok || w.Replicas < 2 {
+		return nil
+	}
+	if ss.Spec.PodManagementPolicy == appsv1.ParallelPodManagement {
+		return []Finding{NewFinding(r, w, true,
+			"This StatefulSet uses the Parallel podManagementPolicy. Pods can be started and stopped at the same time.",
+		)}
+	}
+	return []Finding{NewFinding(r, w, false,
+		"This StatefulSet uses the OrderedReady podManagementPolicy. Pods are started one at a time and a Pod that can't become ready blocks every Pod after it. This slows down recovery when several Pods are evicted at once, e.g. during Node upgrades. Read more here: https://kubernetes.io/docs/concepts/workloads/controllers/statefulset/#pod-management-policies",
+		"if the Pods don't depend on each other starting in order, set podManagementPolicy to Parallel.",
+	)}
+}
+
+// statefulSetUpdateStrategyRule checks that a StatefulSet's updateStrategy rolls
+// changes out to every Pod
+type statefulSetUpdateStrategyRule struct{}
+
+func (statefulSetUpdateStrategyRule) ID() string { return RuleStatefulSetUpdateStrategy }
+
+func (statefulSetUpdateStrategyRule) Description() string {
+	return "StatefulSets should use the RollingUpdate updateStrategy without a partition so that changes reach every Pod."
+}
+
+func (statefulSetUpdateStrategyRule) Severity() Severity { return SeverityWarning }
+
+func (r statefulSetUpdateStrategyRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	ss, ok := w.Object.(*appsv1.StatefulSet)
+	if !ok {
+		return nil
+	}
+	strategy := ss.Spec.UpdateStrategy
+	if strategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		return []Finding{NewFinding(r, w, false,
+			"This StatefulSet uses the OnDelete updateStrategy. Pods are only updated when they are deleted, so changes are rolled out unpredictably during events like Node upgrades. Read more here: https://kubernetes.io/docs/concepts/workloads/controllers/statefulset/#update-strategies",
+			"use the RollingUpdate updateStrategy.",
+		)}
+	}
+
+	var partition int32
+	if strategy.RollingUpdate != nil && strategy.RollingUpdate.Partition != nil {
+		partition = *strategy.RollingUpdate.Partition
+	}
+	if partition >= w.Replicas && partition > 0 {
+		return []Finding{NewFinding(r, w, false,
+			fmt.Sprintf("This StatefulSet has updateStrategy.rollingUpdate.partition set to %v with %v replicas. No Pods will be updated by a rollout.", partition, w.Replicas),
+			"if a staged rollout has finished, set updateStrategy.rollingUpdate.partition back to 0.",
+		)}
+	}
+	if partition > 0 {
+		return []Finding{NewFinding(r, w, false,
+			fmt.Sprintf("This StatefulSet has updateStrategy.rollingUpdate.partition set to %v. Pods with an ordinal below %v won't be updated by a rollout, which leaves the StatefulSet running mixed versions.", partition, partition),
+			"if a staged rollout has finished, set updateStrategy.rollingUpdate.partition back to 0.",
+		)}
+	}
+	return []Finding{NewFinding(r, w, true,
+		"This StatefulSet uses the RollingUpdate updateStrategy without a partition.",
+	)}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_statefulSetRules(t *testing.T) {
+	var replicas int32 = 3
+	var partition int32 = 1
+	var fullPartition int32 = 3
+
+	tests := []struct {
+		name        string
+		statefulSet appsv1.StatefulSet
+		want        map[string]bool
+	}{
+		{
+			name: "A StatefulSet with default settings should fail the pod management check only",
+			statefulSet: appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					Replicas: &replicas,
+				},
+			},
+			want: map[string]bool{
+				RuleStatefulSetPodManagement:  false,
+				RuleStatefulSetUpdateStrategy: true,
+			},
+		},
+		{
+			name: "A Parallel StatefulSet with a partition should fail the update strategy check only",
+			statefulSet: appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					Replicas:            &replicas,
+					PodManagementPolicy: appsv1.ParallelPodManagement,
+					UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+						Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+						RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition},
+					},
+				},
+			},
+			want: map[string]bool{
+				RuleStatefulSetPodManagement:  true,
+				RuleStatefulSetUpdateStrategy: false,
+			},
+		},
+		{
+			name: "A StatefulSet with a partition covering every replica should fail the update strategy check",
+			statefulSet: appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					Replicas:            &replicas,
+					PodManagementPolicy: appsv1.ParallelPodManagement,
+					UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+						Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+						RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &fullPartition},
+					},
+				},
+			},
+			want: map[string]bool{
+				RuleStatefulSetPodManagement:  true,
+				RuleStatefulSetUpdateStrategy: false,
+			},
+		},
+		{
+			name: "A StatefulSet using OnDelete should fail the update strategy check",
+			statefulSet: appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					Replicas:            &replicas,
+					PodManagementPolicy: appsv1.ParallelPodManagement,
+					UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+						Type: appsv1.OnDeleteStatefulSetStrategyType,
+					},
+				},
+			},
+			want: map[string]bool{
+				RuleStatefulSetPodManagement:  true,
+				RuleStatefulSetUpdateStrategy: false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.statefulSet.ObjectMeta = metav1.ObjectMeta{Name: "foo", Namespace: "default"}
+			w := buildStatefulSetWorkload(tt.statefulSet)
+			s := newClusterState(fake.NewSimpleClientset(), DefaultConfig())
+			got := map[string]bool{}
+			for _, r := range []Rule{statefulSetPodManagementRule{}, statefulSetUpdateStrategyRule{}} {
+				for _, f := range r.Evaluate(w, s) {
+					got[f.RuleID] = f.Passed
+				}
+			}
+			for id, want := range tt.want {
+				if passed, ok := got[id]; !ok || passed != want {
+					t.Errorf("%s passed = %v, want %v", id, passed, want)
+				}
+			}
+		})
+	}
+}
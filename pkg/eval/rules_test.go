@@ -0,0 +1,237 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"reflect"
+	"testing"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// testWorkload returns a Deployment Workload in the default Namespace whose Pods are labelled app=foo
+func testWorkload(replicas int32) *Workload {
+	w := &Workload{
+		Kind:      KindDeployment,
+		Name:      "foo",
+		Namespace: "default",
+		Replicas:  replicas,
+		Labels:    "app=foo",
+		Selectors: map[string]string{"app": "foo"},
+		Selector:  &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+	}
+	w.Template.Labels = map[string]string{"app": "foo"}
+	return w
+}
+
+// testPDB returns a PodDisruptionBudget selecting Pods labelled app=foo
+func testPDB(name string, spec policyv1.PodDisruptionBudgetSpec) *policyv1.PodDisruptionBudget {
+	spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       spec,
+	}
+}
+
+// evaluateRule carries out a single Rule against a Workload and returns whether
+// each finding passed, or nil if the Rule didn't apply
+func evaluateRule(r Rule, w *Workload, objects ...runtime.Object) []bool {
+	s := newClusterState(fake.NewSimpleClientset(objects...), DefaultConfig())
+	var results []bool
+	for _, f := range r.Evaluate(w, s) {
+		results = append(results, f.Passed)
+	}
+	return results
+}
+
+func Test_minReplicasRule(t *testing.T) {
+	tests := []struct {
+		name     string
+		replicas int32
+		want     []bool
+	}{
+		{
+			name:     "A single replica should fail",
+			replicas: 1,
+			want:     []bool{false},
+		},
+		{
+			name:     "The minimum number of replicas should pass",
+			replicas: 2,
+			want:     []bool{true},
+		},
+		{
+			name:     "A workload scaled to zero should be skipped",
+			replicas: 0,
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateRule(minReplicasRule{}, testWorkload(tt.replicas))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("minReplicasRule.Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_pdbOverlapRule(t *testing.T) {
+	one := intstr.FromInt(1)
+
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		want    []bool
+	}{
+		{
+			name:    "A workload without a PodDisruptionBudget should be skipped",
+			objects: nil,
+			want:    nil,
+		},
+		{
+			name:    "A workload covered by a single PodDisruptionBudget should pass",
+			objects: []runtime.Object{testPDB("foo", policyv1.PodDisruptionBudgetSpec{MinAvailable: &one})},
+			want:    []bool{true},
+		},
+		{
+			name: "A workload covered by several PodDisruptionBudgets should fail",
+			objects: []runtime.Object{
+				testPDB("foo", policyv1.PodDisruptionBudgetSpec{MinAvailable: &one}),
+				testPDB("bar", policyv1.PodDisruptionBudgetSpec{MinAvailable: &one}),
+			},
+			want: []bool{false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateRule(pdbOverlapRule{}, testWorkload(2), tt.objects...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pdbOverlapRule.Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_pdbDisruptionRule(t *testing.T) {
+	zero := intstr.FromInt(0)
+	one := intstr.FromInt(1)
+	three := intstr.FromInt(3)
+	all := intstr.FromString("100%")
+	tenPercent := intstr.FromString("10%")
+	malformed := intstr.FromString("half")
+	var minReplicas int32 = 3
+
+	tests := []struct {
+		name     string
+		replicas int32
+		objects  []runtime.Object
+		want     []bool
+	}{
+		{
+			name:     "A maxUnavailable of 0 should fail",
+			replicas: 3,
+			objects:  []runtime.Object{testPDB("foo", policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &zero})},
+			want:     []bool{false},
+		},
+		{
+			name:     "A minAvailable equal to replicas should fail",
+			replicas: 3,
+			objects:  []runtime.Object{testPDB("foo", policyv1.PodDisruptionBudgetSpec{MinAvailable: &three})},
+			want:     []bool{false},
+		},
+		{
+			name:     "A minAvailable lower than replicas should pass",
+			replicas: 3,
+			objects:  []runtime.Object{testPDB("foo", policyv1.PodDisruptionBudgetSpec{MinAvailable: &one})},
+			want:     []bool{true},
+		},
+		{
+			name:     "A minAvailable equal to the HorizontalPodAutoscaler min replicas should fail",
+			replicas: 5,
+			objects: []runtime.Object{
+				testPDB("foo", policyv1.PodDisruptionBudgetSpec{MinAvailable: &three}),
+				&autoscalingv1.HorizontalPodAutoscaler{
+					ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+					Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+						ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{Kind: KindDeployment, Name: "foo"},
+						MinReplicas:    &minReplicas,
+						MaxReplicas:    10,
+					},
+				},
+			},
+			want: []bool{false},
+		},
+		{
+			name:     "A minAvailable of 100% should fail",
+			replicas: 3,
+			objects:  []runtime.Object{testPDB("foo", policyv1.PodDisruptionBudgetSpec{MinAvailable: &all})},
+			want:     []bool{false},
+		},
+		{
+			name:     "A maxUnavailable of 10% should pass since it is rounded up",
+			replicas: 3,
+			objects:  []runtime.Object{testPDB("foo", policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &tenPercent})},
+			want:     []bool{true},
+		},
+		{
+			name:     "A PodDisruptionBudget without minAvailable or maxUnavailable should be skipped",
+			replicas: 3,
+			objects:  []runtime.Object{testPDB("foo", policyv1.PodDisruptionBudgetSpec{})},
+			want:     nil,
+		},
+		{
+			name:     "Each PodDisruptionBudget covering the workload should be evaluated",
+			replicas: 3,
+			objects: []runtime.Object{
+				testPDB("foo", policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &one}),
+				testPDB("bar", policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &zero}),
+			},
+			want: []bool{false, true},
+		},
+		{
+			name:     "A workload scaled to zero should be skipped",
+			replicas: 0,
+			objects:  []runtime.Object{testPDB("foo", policyv1.PodDisruptionBudgetSpec{MinAvailable: &one})},
+			want:     nil,
+		},
+		{
+			name:     "A malformed percentage should fail",
+			replicas: 3,
+			objects:  []runtime.Object{testPDB("foo", policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &malformed})},
+			want:     []bool{false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateRule(pdbDisruptionRule{}, testWorkload(tt.replicas), tt.objects...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pdbDisruptionRule.Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// topologyKeys are the Node labels replicas should be spread across along with the
+// severity of not doing so
+var topologyKeys = []struct {
+	key      string
+	domain   string
+	severity Severity
+	// multiZone is set for keys that only matter when the cluster spans several zones
+	multiZone bool
+}{
+	{corev1.LabelHostname, "Node", SeverityWarning, false},
+	{corev1.LabelTopologyZone, "zone", SeverityInfo, true},
+}
+
+// topologySpreadRule checks that multi-replica workloads spread their Pods across Nodes and zones
+type topologySpreadRule struct{}
+
+func (topologySpreadRule) ID() string { return RuleTopologySpread }
+
+func (topologySpreadRule) Description() string {
+	return "Workloads with several replicas should spread them across Nodes and zones using topologySpreadConstraints or pod anti-affinity."
+}
+
+func (topologySpreadRule) Severity() Severity { return SeverityWarning }
+
+func (r topologySpreadRule) Evaluate(w *Workload, s *ClusterState) []Finding {
+	if !w.Scalable() {
+		return nil
+	}
+	replicas := w.Replicas
+	if hpa, _ := s.HPA(w); hpa != nil {
+		replicas = hpa.MaxReplicas
+	}
+	if replicas < 2 {
+		return nil
+	}
+
+	var findings []Finding
+	for _, topology := range topologyKeys {
+		// Nothing can be spread across zones in a single zone cluster, or one whose
+		// Nodes can't be listed
+		if topology.multiZone && s.Zones() <= 1 {
+			continue
+		}
+		if spreadsAcross(w, topology.key) {
+			f := NewFinding(r, w, true,
+				fmt.Sprintf("Pods are spread across %ss using %s.", topology.domain, topology.key),
+			)
+			f.Subject = topology.key
+			findings = append(findings, f)
+			continue
+		}
+		f := NewFinding(r, w, false,
+			fmt.Sprintf("Pods have neither topologySpreadConstraints nor pod anti-affinity across %s that select them. Every replica could be scheduled into the same %s and go down together with it. Read more here: https://kubernetes.io/docs/concepts/scheduling-eviction/topology-spread-constraints/", topology.key, topology.domain),
+			fmt.Sprintf("add a topologySpreadConstraint with topologyKey %s and a labelSelector matching these Pods.", topology.key),
+			fmt.Sprintf("alternatively, add a preferred podAntiAffinity term with topologyKey %s.", topology.key),
+		)
+		f.Severity = topology.severity
+		f.Subject = topology.key
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// spreadsAcross returns whether or not the workload's Pods are spread across topologyKey
+// by a topologySpreadConstraint or pod anti-affinity term selecting the Pods themselves
+func spreadsAcross(w *Workload, topologyKey string) bool {
+	podLabels := labels.Set(w.PodLabels())
+	selects := func(selector *metav1.LabelSelector) bool {
+		s, err := metav1.LabelSelectorAsSelector(selector)
+		return err == nil && s.Matches(podLabels)
+	}
+
+	spec := w.Template.Spec
+	for _, c := range spec.TopologySpreadConstraints {
+		if c.TopologyKey == topologyKey && selects(c.LabelSelector) {
+			return true
+		}
+	}
+	if spec.Affinity == nil || spec.Affinity.PodAntiAffinity == nil {
+		return false
+	}
+	antiAffinity := spec.Affinity.PodAntiAffinity
+	terms := antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	for _, weighted := range antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		terms = append(terms, weighted.PodAffinityTerm)
+	}
+	for _, term := range terms {
+		if term.TopologyKey == topologyKey && selects(term.LabelSelector) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func Test_topologySpreadRule(t *testing.T) {
+	selectsFoo := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}
+	selectsBar := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "bar"}}
+
+	multiZone := []runtime.Object{testNode("a1", "a"), testNode("b1", "b")}
+	singleZone := []runtime.Object{testNode("a1", "a"), testNode("a2", "a")}
+
+	tests := []struct {
+		name         string
+		replicas     int32
+		nodes        []runtime.Object
+		spec         corev1.PodSpec
+		want         []bool
+		wantSeverity []Severity
+	}{
+		{
+			name:     "A single replica workload should be skipped",
+			replicas: 1,
+			nodes:    multiZone,
+			want:     nil,
+		},
+		{
+			name:         "A multi-replica workload without any spread should fail for hostname and zone",
+			replicas:     3,
+			nodes:        multiZone,
+			want:         []bool{false, false},
+			wantSeverity: []Severity{SeverityWarning, SeverityInfo},
+		},
+		{
+			name:     "topologySpreadConstraints across hostname and zone should pass",
+			replicas: 3,
+			nodes:    multiZone,
+			spec: corev1.PodSpec{
+				TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+					{MaxSkew: 1, TopologyKey: corev1.LabelHostname, WhenUnsatisfiable: corev1.ScheduleAnyway, LabelSelector: selectsFoo},
+					{MaxSkew: 1, TopologyKey: corev1.LabelTopologyZone, WhenUnsatisfiable: corev1.ScheduleAnyway, LabelSelector: selectsFoo},
+				},
+			},
+			want:         []bool{true, true},
+			wantSeverity: []Severity{SeverityWarning, SeverityWarning},
+		},
+		{
+			name:     "Preferred pod anti-affinity across hostname should pass for hostname only",
+			replicas: 3,
+			nodes:    multiZone,
+			spec: corev1.PodSpec{
+				Affinity: &corev1.Affinity{
+					PodAntiAffinity: &corev1.PodAntiAffinity{
+						PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+							{Weight: 100, PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: corev1.LabelHostname, LabelSelector: selectsFoo}},
+						},
+					},
+				},
+			},
+			want:         []bool{true, false},
+			wantSeverity: []Severity{SeverityWarning, SeverityInfo},
+		},
+		{
+			name:     "topologySpreadConstraints selecting other Pods should fail",
+			replicas: 3,
+			nodes:    multiZone,
+			spec: corev1.PodSpec{
+				TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+					{MaxSkew: 1, TopologyKey: corev1.LabelHostname, WhenUnsatisfiable: corev1.ScheduleAnyway, LabelSelector: selectsBar},
+				},
+			},
+			want:         []bool{false, false},
+			wantSeverity: []Severity{SeverityWarning, SeverityInfo},
+		},
+		{
+			name:         "Zones should be skipped in a single zone cluster",
+			replicas:     3,
+			nodes:        singleZone,
+			want:         []bool{false},
+			wantSeverity: []Severity{SeverityWarning},
+		},
+		{
+			name:         "Zones should be skipped when no Node reports a zone",
+			replicas:     3,
+			want:         []bool{false},
+			wantSeverity: []Severity{SeverityWarning},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testWorkload(tt.replicas)
+			w.Template.Spec = tt.spec
+			var got []bool
+			var gotSeverity []Severity
+			for _, f := range (topologySpreadRule{}).Evaluate(w, hpaClusterState(tt.nodes...)) {
+				got = append(got, f.Passed)
+				gotSeverity = append(gotSeverity, f.Severity)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotSeverity, tt.wantSeverity) {
+				t.Errorf("Evaluate() severity = %v, want %v", gotSeverity, tt.wantSeverity)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ClusterState gives rules access to the resources in the cluster that relate
+// to the workload being evaluated
+// Lookups are cached so that rules can call them freely, and each kind of object is
+// listed once per Namespace and matched to workloads in memory
+type ClusterState struct {
+	Clientset  kubernetes.Interface
+	Thresholds Thresholds
+	config     *Config
+	hpaVersion string
+	hpas       map[string]*hpaDescription
+	pdbs       map[string][]*pdbDescription
+	placements map[string][]PodPlacement
+	// listed holds the error returned by each list carried out, keyed by kind and Namespace
+	listed               map[string]error
+	namespaceHPAs        map[string][]autoscalingv2.HorizontalPodAutoscaler
+	namespacePDBs        map[string][]policyv1.PodDisruptionBudget
+	namespacePods        map[string][]corev1.Pod
+	namespaceReplicaSets map[string][]appsv1.ReplicaSet
+	// nodeLabels is nil until Nodes have been listed, and stays nil if they can't be
+	nodeLabels  map[string]map[string]string
+	nodesListed bool
+}
+
+// PodPlacement describes where a ready Pod is running
+type PodPlacement struct {
+	Pod  string
+	Node string
+	Zone string
+}
+
+// newClusterState returns an empty ClusterState backed by clientset
+func newClusterState(clientset kubernetes.Interface, config *Config) *ClusterState {
+	return &ClusterState{
+		Clientset:  clientset,
+		Thresholds: config.Thresholds,
+		config:     config,
+		hpas:       map[string]*hpaDescription{},
+		pdbs:       map[string][]*pdbDescription{},
+		placements: map[string][]PodPlacement{},
+
+		listed:               map[string]error{},
+		namespaceHPAs:        map[string][]autoscalingv2.HorizontalPodAutoscaler{},
+		namespacePDBs:        map[string][]policyv1.PodDisruptionBudget{},
+		namespacePods:        map[string][]corev1.Pod{},
+		namespaceReplicaSets: map[string][]appsv1.ReplicaSet{},
+	}
+}
+
+// workloadKey uniquely identifies a workload within the cluster
+func workloadKey(w *Workload) string {
+	return w.Kind + "/" + w.Namespace + "/" + w.Name
+}
+
+// list carries out fn the first time a kind of object is needed in a Namespace and
+// returns its error, logging it once
+func (s *ClusterState) list(kind, namespace string, fn func() error) error {
+	key := kind + "/" + namespace
+	if err, ok := s.listed[key]; ok {
+		return err
+	}
+	err := fn()
+	if err != nil {
+		log.Warnf("Unable to list %s in Namespace %s, skipping checks that need them: %s", kind, namespace, err)
+	}
+	s.listed[key] = err
+	return err
+}
+
+// HPA returns the HorizontalPodAutoscaler matched to the workload, or nil if there isn't one
+// ok is false if HorizontalPodAutoscalers couldn't be listed
+func (s *ClusterState) HPA(w *Workload) (info *HPAInfo, ok bool) {
+	key := workloadKey(w)
+	hpa, found := s.hpas[key]
+	if !found {
+		err := s.list("HorizontalPodAutoscalers", w.Namespace, func() (err error) {
+			if s.hpaVersion == "" {
+				s.hpaVersion = preferredHPAVersion(s.Clientset)
+			}
+			s.namespaceHPAs[w.Namespace], err = listHorizontalPodAutoscalers(s.Clientset, w.Namespace, s.hpaVersion)
+			return err
+		})
+		if err != nil {
+			return nil, false
+		}
+		hpa = returnHorizontalPodAutoscalers(s.namespaceHPAs[w.Namespace], w, s.config.HPALabelFallback)
+		s.hpas[key] = hpa
+	}
+	if hpa.name == "" {
+		return nil, true
+	}
+	info = &HPAInfo{
+		Name:            hpa.name,
+		MinReplicas:     hpa.min,
+		MaxReplicas:     hpa.max,
+		Behavior:        hpa.behavior,
+		CurrentReplicas: hpa.currentReplicas,
+		DesiredReplicas: hpa.desiredReplicas,
+		Conditions:      hpa.conditions,
+	}
+	for _, m := range hpa.metrics {
+		info.Metrics = append(info.Metrics, describeMetric(m))
+	}
+	return info, true
+}
+
+// PDB returns the PodDisruptionBudget matched to the workload, or nil if there isn't one
+// If several PodDisruptionBudgets cover the workload, the first is returned
+// ok is false if PodDisruptionBudgets couldn't be listed
+func (s *ClusterState) PDB(w *Workload) (info *PDBInfo, ok bool) {
+	pdbs, ok := s.PDBs(w)
+	if len(pdbs) == 0 {
+		return nil, ok
+	}
+	return &pdbs[0], true
+}
+
+// PDBs returns every PodDisruptionBudget covering the workload's Pods
+// ok is false if PodDisruptionBudgets couldn't be listed
+func (s *ClusterState) PDBs(w *Workload) (infos []PDBInfo, ok bool) {
+	key := workloadKey(w)
+	pdbs, found := s.pdbs[key]
+	if !found {
+		err := s.list("PodDisruptionBudgets", w.Namespace, func() (err error) {
+			s.namespacePDBs[w.Namespace], err = listPodDisruptionBudgets(s.Clientset, w.Namespace)
+			return err
+		})
+		if err != nil {
+			return nil, false
+		}
+		pdbs = returnPodDisruptionBudgets(s.namespacePDBs[w.Namespace], w)
+		s.pdbs[key] = pdbs
+	}
+	for _, pdb := range pdbs {
+		infos = append(infos, PDBInfo{
+			Name:               pdb.name,
+			AvailabilityConfig: pdb.availabilityConfig,
+			MinAvailable:       pdb.minAvailable,
+			MaxUnavailable:     pdb.maxUnavailable,
+		})
+	}
+	return infos, true
+}
+
+// Zones returns the number of zones the cluster's Nodes are spread across, or 0 if
+// Nodes can't be listed
+func (s *ClusterState) Zones() int {
+	zones := map[string]bool{}
+	for _, nodeLabels := range s.nodes() {
+		if zone, ok := nodeLabels[corev1.LabelTopologyZone]; ok {
+			zones[zone] = true
+		}
+	}
+	return len(zones)
+}
+
+// Placement returns where each of the workload's ready Pods is running
+// ok is false if Pods or Nodes couldn't be listed
+func (s *ClusterState) Placement(w *Workload) (placements []PodPlacement, ok bool) {
+	nodes := s.nodes()
+	if nodes == nil {
+		return nil, false
+	}
+	key := workloadKey(w)
+	// A nil entry means listing Pods failed
+	if cached, found := s.placements[key]; found {
+		return cached, cached != nil
+	}
+	err := s.list("Pods", w.Namespace, func() (err error) {
+		s.namespacePods[w.Namespace], err = listPods(s.Clientset, w.Namespace)
+		return err
+	})
+	if err == nil && ownsPodsThroughReplicaSets(w) {
+		err = s.list("ReplicaSets", w.Namespace, func() (err error) {
+			s.namespaceReplicaSets[w.Namespace], err = listReplicaSets(s.Clientset, w.Namespace)
+			return err
+		})
+	}
+	if err != nil {
+		s.placements[key] = nil
+		return nil, false
+	}
+	pods, err := returnReadyPods(s.namespacePods[w.Namespace], s.namespaceReplicaSets[w.Namespace], w)
+	if err != nil {
+		log.Errorf("Error: %s", err)
+		s.placements[key] = nil
+		return nil, false
+	}
+	placements = []PodPlacement{}
+	for _, pod := range pods {
+		placements = append(placements, PodPlacement{
+			Pod:  pod.Name,
+			Node: pod.Spec.NodeName,
+			Zone: nodes[pod.Spec.NodeName][corev1.LabelTopologyZone],
+		})
+	}
+	s.placements[key] = placements
+	return placements, true
+}
+
+// nodes returns the labels of every Node keyed by Node name, listing them the first time
+// If Nodes can't be listed, e.g. without cluster-scoped permissions, nil is returned
+func (s *ClusterState) nodes() map[string]map[string]string {
+	if !s.nodesListed {
+		s.nodesListed = true
+		nodeLabels, err := returnNodeLabels(s.Clientset)
+		if err != nil {
+			log.Warnf("Unable to list Nodes, skipping checks of where Pods are running: %s", err)
+		} else {
+			s.nodeLabels = nodeLabels
+		}
+	}
+	return s.nodeLabels
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"fmt"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestClusterState_listsOncePerNamespace(t *testing.T) {
+	var replicas int32 = 2
+	var workloads []*Workload
+	for _, name := range []string{"foo", "bar", "baz"} {
+		workloads = append(workloads, buildDeploymentWorkload(appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name)},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+			},
+		}))
+	}
+	clientset := fake.NewSimpleClientset()
+	checkWorkloads(newClusterState(clientset, DefaultConfig()), workloads)
+
+	lists := map[string]int{}
+	for _, action := range clientset.Actions() {
+		if action.GetVerb() == "list" {
+			lists[action.GetResource().Resource]++
+		}
+	}
+	for _, resource := range []string{"horizontalpodautoscalers", "poddisruptionbudgets", "pods", "replicasets"} {
+		if lists[resource] != 1 {
+			t.Errorf("checkWorkloads() listed %s %v times for 3 workloads in one Namespace, want 1", resource, lists[resource])
+		}
+	}
+}
+
+func TestClusterState_listErrors(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	for _, resource := range []string{"horizontalpodautoscalers", "poddisruptionbudgets"} {
+		resource := resource
+		clientset.PrependReactor("list", resource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.NewForbidden(schema.GroupResource{Resource: resource}, "", fmt.Errorf("cannot list resource %q in the Namespace \"default\"", resource))
+		})
+	}
+	s := newClusterState(clientset, DefaultConfig())
+	if _, ok := s.HPA(testWorkload(1)); ok {
+		t.Errorf("ClusterState.HPA() ok = true when HorizontalPodAutoscalers can't be listed")
+	}
+	if _, ok := s.PDBs(testWorkload(1)); ok {
+		t.Errorf("ClusterState.PDBs() ok = true when PodDisruptionBudgets can't be listed")
+	}
+
+	// Rules that depend on them shouldn't report anything rather than fail
+	report := evaluateWorkload(testWorkload(1), s)
+	for _, f := range report.Findings {
+		switch f.RuleID {
+		case RuleHPAConfigured, RuleMinReplicas, RulePDBConfigured, RulePDBOverlap, RulePDBDisruption:
+			t.Errorf("evaluateWorkload() reported %s when the objects it needs can't be listed: %s", f.RuleID, f.Message)
+		}
+	}
+}
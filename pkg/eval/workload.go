@@ -0,0 +1,193 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Kinds of workloads that are evaluated
+const (
+	KindDaemonSet   = "DaemonSet"
+	KindDeployment  = "Deployment"
+	KindRollout     = "Rollout"
+	KindStatefulSet = "StatefulSet"
+)
+
+// Workload describes a single workload being evaluated with information
+// that can be used to calculate risk
+type Workload struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Replicas  int32
+	// Labels is Selectors formatted as a label selector string
+	Labels    string
+	Selectors map[string]string
+	// Selector is the full spec.selector, including matchExpressions
+	Selector *metav1.LabelSelector
+	Template corev1.PodTemplateSpec
+	// Object is the underlying Kubernetes object, e.g. *appsv1.Deployment
+	// Argo Rollouts are *unstructured.Unstructured
+	Object interface{}
+}
+
+// Scalable returns whether or not the workload's replica count is set by its spec
+// DaemonSets run a Pod per Node instead, so rules about replicas,
+// HorizontalPodAutoscalers and PodDisruptionBudgets don't apply to them
+func (w *Workload) Scalable() bool {
+	return w.Kind != KindDaemonSet
+}
+
+// PodLabels returns the labels the workload's Pods are created with
+// The selector labels are used if the workload has no Pod template of its own,
+// e.g. an Argo Rollout using spec.workloadRef
+func (w *Workload) PodLabels() map[string]string {
+	if len(w.Template.Labels) > 0 {
+		return w.Template.Labels
+	}
+	return w.Selectors
+}
+
+// selectorString formats matchLabels as a label selector string
+func selectorString(matchLabels map[string]string) string {
+	var container []string
+	for k, v := range matchLabels {
+		container = append(container, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(container)
+	return strings.Join(container, ",")
+}
+
+// buildDeploymentWorkload returns a Workload with information regarding a Deployment
+func buildDeploymentWorkload(d appsv1.Deployment) *Workload {
+	var replicas int32 = 1
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	var matchLabels map[string]string
+	if d.Spec.Selector != nil {
+		matchLabels = d.Spec.Selector.MatchLabels
+	}
+	return &Workload{
+		Kind:      KindDeployment,
+		Name:      d.Name,
+		Namespace: d.Namespace,
+		Replicas:  replicas,
+		Labels:    selectorString(matchLabels),
+		Selectors: matchLabels,
+		Selector:  d.Spec.Selector,
+		Template:  d.Spec.Template,
+		Object:    &d,
+	}
+}
+
+// buildStatefulSetWorkload returns a Workload with information regarding a StatefulSet
+func buildStatefulSetWorkload(s appsv1.StatefulSet) *Workload {
+	var replicas int32 = 1
+	if s.Spec.Replicas != nil {
+		replicas = *s.Spec.Replicas
+	}
+	var matchLabels map[string]string
+	if s.Spec.Selector != nil {
+		matchLabels = s.Spec.Selector.MatchLabels
+	}
+	return &Workload{
+		Kind:      KindStatefulSet,
+		Name:      s.Name,
+		Namespace: s.Namespace,
+		Replicas:  replicas,
+		Labels:    selectorString(matchLabels),
+		Selectors: matchLabels,
+		Selector:  s.Spec.Selector,
+		Template:  s.Spec.Template,
+		Object:    &s,
+	}
+}
+
+// buildDaemonSetWorkload returns a Workload with information regarding a DaemonSet
+// Replicas is the number of Nodes the DaemonSet should be running on
+func buildDaemonSetWorkload(d appsv1.DaemonSet) *Workload {
+	var matchLabels map[string]string
+	if d.Spec.Selector != nil {
+		matchLabels = d.Spec.Selector.MatchLabels
+	}
+	return &Workload{
+		Kind:      KindDaemonSet,
+		Name:      d.Name,
+		Namespace: d.Namespace,
+		Replicas:  d.Status.DesiredNumberScheduled,
+		Labels:    selectorString(matchLabels),
+		Selectors: matchLabels,
+		Selector:  d.Spec.Selector,
+		Template:  d.Spec.Template,
+		Object:    &d,
+	}
+}
+
+// buildRolloutWorkload returns a Workload with information regarding an Argo Rollout
+// Rollouts that reference another workload using spec.workloadRef have an empty Template
+func buildRolloutWorkload(u unstructured.Unstructured) (*Workload, error) {
+	replicas, found, err := unstructured.NestedInt64(u.Object, "spec", "replicas")
+	if err != nil {
+		return nil, fmt.Errorf("error reading spec.replicas of Rollout %s/%s: %w", u.GetNamespace(), u.GetName(), err)
+	}
+	if !found {
+		replicas = 1
+	}
+	var selector *metav1.LabelSelector
+	if rawSelector, found, _ := unstructured.NestedMap(u.Object, "spec", "selector"); found {
+		selector = &metav1.LabelSelector{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawSelector, selector); err != nil {
+			return nil, fmt.Errorf("error reading spec.selector of Rollout %s/%s: %w", u.GetNamespace(), u.GetName(), err)
+		}
+	}
+	var matchLabels map[string]string
+	if selector != nil {
+		matchLabels = selector.MatchLabels
+	}
+	var template corev1.PodTemplateSpec
+	if rawTemplate, found, _ := unstructured.NestedMap(u.Object, "spec", "template"); found {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawTemplate, &template); err != nil {
+			return nil, fmt.Errorf("error reading spec.template of Rollout %s/%s: %w", u.GetNamespace(), u.GetName(), err)
+		}
+	}
+	return &Workload{
+		Kind:      KindRollout,
+		Name:      u.GetName(),
+		Namespace: u.GetNamespace(),
+		Replicas:  int32(replicas),
+		Labels:    selectorString(matchLabels),
+		Selectors: matchLabels,
+		Selector:  selector,
+		Template:  template,
+		Object:    &u,
+	}, nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package eval
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// testRollout returns an Argo Rollout with the given number of replicas
+func testRollout(replicas int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "Rollout",
+			"metadata": map[string]interface{}{
+				"name":      "foo",
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"replicas": replicas,
+				"selector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{"app": "foo"},
+				},
+				"template": map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"labels": map[string]interface{}{"app": "foo"},
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "foo", "image": "foo:latest"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_returnEligibleRollouts(t *testing.T) {
+	listKinds := map[schema.GroupVersionResource]string{rolloutResource: "RolloutList"}
+
+	notInstalled := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	notInstalled.PrependReactor("list", "rollouts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewNotFound(rolloutResource.GroupResource(), "")
+	})
+
+	forbidden := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	forbidden.PrependReactor("list", "rollouts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewForbidden(rolloutResource.GroupResource(), "", fmt.Errorf("cannot list resource \"rollouts\" in the Namespace \"default\""))
+	})
+
+	tests := []struct {
+		name    string
+		client  *dynamicfake.FakeDynamicClient
+		want    int
+		wantErr bool
+	}{
+		{
+			name:   "Rollouts in the given Namespaces should be returned",
+			client: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, testRollout(3)),
+			want:   1,
+		},
+		{
+			name:   "Nothing should be returned if Argo Rollouts isn't installed",
+			client: notInstalled,
+			want:   0,
+		},
+		{
+			name:   "Nothing should be returned without permission to list Rollouts",
+			client: forbidden,
+			want:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := returnEligibleRollouts(tt.client, []string{"default"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("returnEligibleRollouts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got) != tt.want {
+				t.Errorf("returnEligibleRollouts() returned %v Rollouts, want %v", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func Test_rolloutsServed(t *testing.T) {
+	served := fake.NewSimpleClientset()
+	served.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "argoproj.io/v1alpha1", APIResources: []metav1.APIResource{{Name: "rollouts"}}},
+	}
+
+	tests := []struct {
+		name      string
+		clientset kubernetes.Interface
+		want      bool
+	}{
+		{
+			name:      "Rollouts should be served when discovery reports them",
+			clientset: served,
+			want:      true,
+		},
+		{
+			name:      "Rollouts should not be served when discovery doesn't know argoproj.io/v1alpha1",
+			clientset: fake.NewSimpleClientset(),
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rolloutsServed(tt.clientset); got != tt.want {
+				t.Errorf("rolloutsServed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_buildRolloutWorkload(t *testing.T) {
+	w, err := buildRolloutWorkload(*testRollout(3))
+	if err != nil {
+		t.Fatalf("buildRolloutWorkload() error = %v", err)
+	}
+	if w.Kind != KindRollout || w.Replicas != 3 || w.Labels != "app=foo" {
+		t.Errorf("buildRolloutWorkload() = %v/%v/%v, want %v/%v/%v", w.Kind, w.Replicas, w.Labels, KindRollout, 3, "app=foo")
+	}
+	if len(w.Template.Spec.Containers) != 1 || w.Template.Spec.Containers[0].Name != "foo" {
+		t.Errorf("buildRolloutWorkload() template containers = %v, want a single container named foo", w.Template.Spec.Containers)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package render
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/echoboomer/paranoidaf/pkg/eval"
+)
+
+// CSV writes a Report to w as CSV with one row per workload
+// PodDisruptionBudget values are written as configured, e.g. 50%, and as Pod counts
+// A column is added for each check containing pass or fail, or nothing
+// if the check wasn't carried out for that workload
+func CSV(w io.Writer, r *eval.Report) error {
+	rules := eval.Rules()
+	header := []string{
+		"namespace",
+		"kind",
+		"name",
+		"replicas",
+		"labels",
+		"hpaName",
+		"hpaMinReplicas",
+		"hpaMaxReplicas",
+		"pdbName",
+		"pdbMinAvailable",
+		"pdbMaxUnavailable",
+		"pdbMinAvailablePods",
+		"pdbMaxUnavailablePods",
+	}
+	for _, rule := range rules {
+		header = append(header, rule.ID())
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, wl := range r.Workloads {
+		row := []string{
+			wl.Namespace,
+			wl.Kind,
+			wl.Name,
+			fmt.Sprint(wl.Replicas),
+			wl.Labels,
+			"",
+			"",
+			"",
+			"",
+			"",
+			"",
+			"",
+			"",
+		}
+		if wl.HPA != nil {
+			row[5] = wl.HPA.Name
+			row[6] = fmt.Sprint(wl.HPA.MinReplicas)
+			row[7] = fmt.Sprint(wl.HPA.MaxReplicas)
+		}
+		if wl.PDB != nil {
+			row[8] = wl.PDB.Name
+			if wl.PDB.MinAvailable != nil {
+				row[9] = wl.PDB.MinAvailable.String()
+			}
+			if wl.PDB.MaxUnavailable != nil {
+				row[10] = wl.PDB.MaxUnavailable.String()
+			}
+			if v, ok := wl.PDB.AvailabilityConfig["minAvailable"]; ok {
+				row[11] = fmt.Sprint(v)
+			}
+			if v, ok := wl.PDB.AvailabilityConfig["maxUnavailable"]; ok {
+				row[12] = fmt.Sprint(v)
+			}
+		}
+
+		results := map[string]string{}
+		for _, f := range wl.Findings {
+			// A single failure is enough to fail the check
+			if f.Passed && results[f.RuleID] == "" {
+				results[f.RuleID] = "pass"
+			} else if !f.Passed {
+				results[f.RuleID] = "fail"
+			}
+		}
+		for _, rule := range rules {
+			row = append(row, results[rule.ID()])
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,149 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package render
+
+import (
+	"html/template"
+	"io"
+	"sort"
+
+	"github.com/echoboomer/paranoidaf/pkg/eval"
+)
+
+// htmlNamespace groups the workloads in a single Namespace for the HTML report
+type htmlNamespace struct {
+	Name      string
+	Workloads []eval.WorkloadReport
+	Counts    map[eval.Severity]int
+	Passed    int
+}
+
+// htmlReport is the data passed to htmlTemplate
+type htmlReport struct {
+	ClusterName string
+	Namespaces  []*htmlNamespace
+	Counts      map[eval.Severity]int
+	Passed      int
+	Severities  []eval.Severity
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>paranoidaf report - {{ .ClusterName }}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #d0d7de; padding: 0.4em 0.8em; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+details { margin: 0.5em 0 0.5em 1em; }
+summary { cursor: pointer; }
+.passed { color: #1a7f37; }
+.info { color: #0969da; }
+.warning { color: #9a6700; }
+.error { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>paranoidaf report - {{ .ClusterName }}</h1>
+
+<h2>Summary</h2>
+<table>
+<tr><th>Namespace</th><th>Workloads</th><th class="passed">passed</th>{{ range .Severities }}<th class="{{ . }}">{{ . }}</th>{{ end }}</tr>
+{{- range .Namespaces }}
+{{- $ns := . }}
+<tr><td><a href="#ns-{{ .Name }}">{{ .Name }}</a></td><td>{{ len .Workloads }}</td><td>{{ .Passed }}</td>{{ range $.Severities }}<td>{{ index $ns.Counts . }}</td>{{ end }}</tr>
+{{- end }}
+<tr><th>Total</th><th></th><th>{{ .Passed }}</th>{{ range .Severities }}<th>{{ index $.Counts . }}</th>{{ end }}</tr>
+</table>
+
+{{- range .Namespaces }}
+<h2 id="ns-{{ .Name }}">{{ .Name }}</h2>
+{{- range .Workloads }}
+<details>
+<summary>{{ if .Failed }}&#9888;{{ else }}&#9989;{{ end }} {{ .Kind }} <strong>{{ .Name }}</strong></summary>
+<table>
+<tr><th>Replicas</th><td>{{ .Replicas }}</td></tr>
+<tr><th>Labels</th><td>{{ .Labels }}</td></tr>
+<tr><th>HorizontalPodAutoscaler</th><td>{{ with .HPA }}{{ .Name }} (min {{ .MinReplicas }}, max {{ .MaxReplicas }}){{ else }}none{{ end }}</td></tr>
+{{- with .HPA }}
+<tr><th>Metrics</th><td>{{ .MetricsString }}</td></tr>
+<tr><th>Scaling behavior</th><td>{{ .BehaviorString }}</td></tr>
+<tr><th>Autoscaled replicas</th><td>{{ .CurrentReplicas }} current, {{ .DesiredReplicas }} desired</td></tr>
+{{- end }}
+<tr><th>PodDisruptionBudget</th><td>{{ with .PDB }}{{ .Name }} ({{ .String }}){{ else }}none{{ end }}</td></tr>
+</table>
+<table>
+<tr><th>Check</th><th>Result</th><th>Details</th></tr>
+{{- range .Findings }}
+<tr>
+<td>{{ .RuleID }}</td>
+<td>{{ if .Passed }}<span class="passed">passed</span>{{ else }}<span class="{{ .Severity }}">{{ .Severity }}</span>{{ end }}</td>
+<td>{{ .Message }}{{ if .Suggestions }}<ul>{{ range .Suggestions }}<li>Suggestion - {{ . }}</li>{{ end }}</ul>{{ end }}</td>
+</tr>
+{{- end }}
+</table>
+</details>
+{{- end }}
+{{- end }}
+</body>
+</html>
+`))
+
+// HTML writes a Report to w as a single, self-contained HTML page with
+// results grouped by Namespace
+func HTML(w io.Writer, r *eval.Report) error {
+	report := htmlReport{
+		ClusterName: r.ClusterName,
+		Counts:      map[eval.Severity]int{},
+		Severities:  []eval.Severity{eval.SeverityError, eval.SeverityWarning, eval.SeverityInfo},
+	}
+
+	byName := map[string]*htmlNamespace{}
+	for _, wl := range r.Workloads {
+		ns, ok := byName[wl.Namespace]
+		if !ok {
+			ns = &htmlNamespace{
+				Name:   wl.Namespace,
+				Counts: map[eval.Severity]int{},
+			}
+			byName[wl.Namespace] = ns
+			report.Namespaces = append(report.Namespaces, ns)
+		}
+		ns.Workloads = append(ns.Workloads, wl)
+		for _, f := range wl.Findings {
+			if f.Passed {
+				ns.Passed++
+				report.Passed++
+				continue
+			}
+			ns.Counts[f.Severity]++
+			report.Counts[f.Severity]++
+		}
+	}
+	sort.Slice(report.Namespaces, func(i, j int) bool {
+		return report.Namespaces[i].Name < report.Namespaces[j].Name
+	})
+
+	return htmlTemplate.Execute(w, report)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package render
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/echoboomer/paranoidaf/pkg/eval"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnit writes a Report to w as a JUnit XML document
+// Each workload is a testsuite and each check against it is a testcase
+func JUnit(w io.Writer, r *eval.Report) error {
+	suites := junitTestSuites{
+		Name: fmt.Sprintf("paranoidaf %s", r.ClusterName),
+	}
+	for _, wl := range r.Workloads {
+		suite := junitTestSuite{
+			Name: fmt.Sprintf("%s/%s/%s", wl.Namespace, wl.Kind, wl.Name),
+		}
+		seen := map[string]int{}
+		for _, f := range wl.Findings {
+			tc := junitTestCase{
+				Name:      junitTestCaseName(f, seen),
+				ClassName: fmt.Sprintf("%s.%s.%s", wl.Namespace, wl.Kind, wl.Name),
+			}
+			if f.Passed {
+				tc.SystemOut = f.Message
+			} else {
+				text := f.Message
+				for _, s := range f.Suggestions {
+					text += fmt.Sprintf("\nSuggestion - %s", s)
+				}
+				tc.Failure = &junitFailure{
+					Message: f.Message,
+					Type:    strings.ToUpper(string(f.Severity)),
+					Text:    text,
+				}
+				suite.Failures++
+			}
+			suite.Tests++
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// junitTestCaseName returns a name for the finding's testcase that is unique within its
+// testsuite, since CI systems merge testcases with the same name
+// The rule ID is followed by whatever tells apart findings from the same rule, e.g.
+// readiness-probe/app or topology-spread/kubernetes.io/hostname
+func junitTestCaseName(f eval.Finding, seen map[string]int) string {
+	parts := []string{f.RuleID}
+	for _, part := range []string{f.Container, f.PDB, f.Subject} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	name := strings.Join(parts, "/")
+	// Fall back to numbering findings that still share a name, e.g. from custom rules
+	seen[name]++
+	if seen[name] > 1 {
+		name = fmt.Sprintf("%s#%v", name, seen[name])
+	}
+	return name
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/echoboomer/paranoidaf/pkg/eval"
+)
+
+// markdownEscaper escapes characters that would break a Markdown table cell
+var markdownEscaper = strings.NewReplacer("|", "\\|", "\n", " ")
+
+// Markdown writes a Report to w as a compact Markdown table followed by a list
+// of suggestions, suitable for posting as a pull request comment
+func Markdown(w io.Writer, r *eval.Report) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### paranoidaf report for `%s`\n\n", r.ClusterName)
+	if len(r.Workloads) == 0 {
+		fmt.Fprintf(&b, "No workloads were found.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	fmt.Fprintf(&b, "| Namespace | Kind | Workload | Replicas | HPA min/max | PDB | Status |\n")
+	fmt.Fprintf(&b, "| --- | --- | --- | --- | --- | --- | --- |\n")
+	for _, wl := range r.Workloads {
+		hpa := "-"
+		if wl.HPA != nil {
+			hpa = fmt.Sprintf("%v/%v", wl.HPA.MinReplicas, wl.HPA.MaxReplicas)
+		}
+		pdb := "-"
+		if wl.PDB != nil {
+			pdb = wl.PDB.String()
+		}
+		status := ":white_check_mark: passed"
+		if failed := failedFindings(wl.Findings); len(failed) > 0 {
+			status = fmt.Sprintf(":warning: %v failed", len(failed))
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %v | %s | %s | %s |\n",
+			markdownEscaper.Replace(wl.Namespace),
+			wl.Kind,
+			markdownEscaper.Replace(wl.Name),
+			wl.Replicas,
+			hpa,
+			markdownEscaper.Replace(pdb),
+			status,
+		)
+	}
+
+	var suggestions []string
+	for _, f := range failedFindings(r.Findings()) {
+		for _, s := range f.Suggestions {
+			suggestions = append(suggestions, fmt.Sprintf("- **%s/%s/%s** (`%s`): %s", f.Namespace, f.Kind, f.Workload, f.RuleID, s))
+		}
+	}
+	if len(suggestions) > 0 {
+		fmt.Fprintf(&b, "\n#### Suggestions\n\n%s\n", strings.Join(suggestions, "\n"))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// failedFindings returns only the findings whose checks did not pass
+func failedFindings(findings []eval.Finding) []eval.Finding {
+	var failed []eval.Finding
+	for _, f := range findings {
+		if !f.Passed {
+			failed = append(failed, f)
+		}
+	}
+	return failed
+}
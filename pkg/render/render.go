@@ -0,0 +1,70 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package render
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/echoboomer/paranoidaf/pkg/eval"
+)
+
+// Renderer writes a Report to w in a given format
+type Renderer func(w io.Writer, r *eval.Report) error
+
+// renderers maps each supported output format to its Renderer
+var renderers = map[string]Renderer{
+	"csv":      CSV,
+	"html":     HTML,
+	"json":     JSON,
+	"junit":    JUnit,
+	"markdown": Markdown,
+	"sarif":    SARIF,
+	"text":     Text,
+	"yaml":     YAML,
+}
+
+// Formats returns the names of all supported output formats
+func Formats() []string {
+	var formats []string
+	for f := range renderers {
+		formats = append(formats, f)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Supported returns whether or not the given output format can be rendered
+func Supported(format string) bool {
+	_, ok := renderers[format]
+	return ok
+}
+
+// Render writes a Report to w using the requested output format
+func Render(w io.Writer, format string, r *eval.Report) error {
+	renderer, ok := renderers[format]
+	if !ok {
+		return fmt.Errorf("unsupported output format %q, must be one of %v", format, Formats())
+	}
+	return renderer(w, r)
+}
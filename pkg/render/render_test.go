@@ -0,0 +1,246 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/echoboomer/paranoidaf/pkg/eval"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var testReport = &eval.Report{
+	ClusterName: "test",
+	Namespaces:  []string{"default"},
+	Workloads: []eval.WorkloadReport{
+		{
+			Kind:      "Deployment",
+			Name:      "foo",
+			Namespace: "default",
+			Replicas:  1,
+			Labels:    "app=foo",
+			Selectors: map[string]string{"app": "foo"},
+			Findings: []eval.Finding{
+				{
+					RuleID:      eval.RulePDBConfigured,
+					Severity:    eval.SeverityWarning,
+					Kind:        "Deployment",
+					Workload:    "foo",
+					Namespace:   "default",
+					Message:     "This app does not have a PodDisruptionBudget.",
+					Suggestions: []string{"enable a PodDisruptionBudget."},
+				},
+			},
+		},
+	},
+}
+
+func TestRender(t *testing.T) {
+	halfAvailable := intstr.FromString("50%")
+
+	type args struct {
+		format string
+		r      *eval.Report
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "CSV output should contain a row for each workload with the result of each check",
+			args: args{
+				format: "csv",
+				r:      testReport,
+			},
+			want: "default,Deployment,foo,1,app=foo,,,,,,,,,,,,,fail",
+		},
+		{
+			name: "CSV output should contain PodDisruptionBudget values as configured and as Pod counts",
+			args: args{
+				format: "csv",
+				r: &eval.Report{
+					Workloads: []eval.WorkloadReport{
+						{
+							Kind:      "Deployment",
+							Name:      "foo",
+							Namespace: "default",
+							Replicas:  3,
+							PDB: &eval.PDBInfo{
+								Name:               "foo",
+								AvailabilityConfig: map[string]int32{"minAvailable": 2},
+								MinAvailable:       &halfAvailable,
+							},
+						},
+					},
+				},
+			},
+			want: "default,Deployment,foo,3,,,,,foo,50%,,2,,",
+		},
+		{
+			name: "HTML output should group workloads by Namespace",
+			args: args{
+				format: "html",
+				r:      testReport,
+			},
+			want: `<h2 id="ns-default">default</h2>`,
+		},
+		{
+			name: "JSON output should contain the findings for each workload",
+			args: args{
+				format: "json",
+				r:      testReport,
+			},
+			want: `"ruleID": "pdb-configured"`,
+		},
+		{
+			name: "YAML output should contain the findings for each workload",
+			args: args{
+				format: "yaml",
+				r:      testReport,
+			},
+			want: "ruleID: pdb-configured",
+		},
+		{
+			name: "JUnit output should contain a failed testcase for each failed check",
+			args: args{
+				format: "junit",
+				r:      testReport,
+			},
+			want: `<failure message="This app does not have a PodDisruptionBudget." type="WARNING">`,
+		},
+		{
+			name: "JUnit output should name each testsuite after the workload's Namespace, kind and name",
+			args: args{
+				format: "junit",
+				r:      testReport,
+			},
+			want: `<testsuite name="default/Deployment/foo"`,
+		},
+		{
+			name: "Markdown output should contain a row for each workload",
+			args: args{
+				format: "markdown",
+				r:      testReport,
+			},
+			want: "| default | Deployment | foo | 1 | - | - | :warning: 1 failed |",
+		},
+		{
+			name: "SARIF output should contain a result for each failed check",
+			args: args{
+				format: "sarif",
+				r:      testReport,
+			},
+			want: `"fullyQualifiedName": "default/Deployment/foo"`,
+		},
+		{
+			name: "Text output should contain suggestions",
+			args: args{
+				format: "text",
+				r:      testReport,
+			},
+			want: "Suggestion - enable a PodDisruptionBudget.",
+		},
+		{
+			name: "An unsupported format should return an error",
+			args: args{
+				format: "foo",
+				r:      testReport,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			if err := Render(w, tt.args.format, tt.args.r); (err != nil) != tt.wantErr {
+				t.Errorf("Render() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got := w.String(); !strings.Contains(got, tt.want) {
+				t.Errorf("Render() = %v, want it to contain %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJUnit_uniqueTestCaseNames(t *testing.T) {
+	finding := func(ruleID, container, subject string) eval.Finding {
+		return eval.Finding{
+			RuleID:    ruleID,
+			Severity:  eval.SeverityWarning,
+			Kind:      "Deployment",
+			Workload:  "foo",
+			Namespace: "default",
+			Container: container,
+			Subject:   subject,
+			Message:   "Something is wrong.",
+		}
+	}
+	report := &eval.Report{
+		ClusterName: "test",
+		Workloads: []eval.WorkloadReport{
+			{
+				Kind:      "Deployment",
+				Name:      "foo",
+				Namespace: "default",
+				Findings: []eval.Finding{
+					finding(eval.RuleReadinessProbe, "app", ""),
+					finding(eval.RuleReadinessProbe, "sidecar", ""),
+					finding(eval.RuleTopologySpread, "", "kubernetes.io/hostname"),
+					finding(eval.RuleTopologySpread, "", "topology.kubernetes.io/zone"),
+					finding("custom", "", ""),
+					finding("custom", "", ""),
+				},
+			},
+		},
+	}
+
+	w := &bytes.Buffer{}
+	if err := JUnit(w, report); err != nil {
+		t.Fatalf("JUnit() error = %v", err)
+	}
+	var got junitTestSuites
+	if err := xml.Unmarshal(w.Bytes(), &got); err != nil {
+		t.Fatalf("JUnit() produced invalid XML: %v", err)
+	}
+	var names []string
+	for _, tc := range got.Suites[0].TestCases {
+		names = append(names, tc.Name)
+	}
+	want := []string{
+		"readiness-probe/app",
+		"readiness-probe/sidecar",
+		"topology-spread/kubernetes.io/hostname",
+		"topology-spread/topology.kubernetes.io/zone",
+		"custom",
+		"custom#2",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("JUnit() testcase names = %v, want %v", names, want)
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/echoboomer/paranoidaf/pkg/common"
+	"github.com/echoboomer/paranoidaf/pkg/eval"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+// sarifLog is the top level object of a SARIF 2.1.0 document
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// sarifLevel maps a Severity to a SARIF result level
+func sarifLevel(s eval.Severity) string {
+	switch s {
+	case eval.SeverityError:
+		return "error"
+	case eval.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// SARIF writes a Report to w as a SARIF 2.1.0 log
+// Each check is a rule and each failed check against a workload is a result
+// Kubernetes objects don't live in files, so each result is located using
+// a URI made up of the cluster, Namespace, kind, and name of the workload
+func SARIF(w io.Writer, r *eval.Report) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "paranoidaf",
+				Version:        common.Version,
+				InformationURI: "https://github.com/echoboomer/paranoidaf",
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	ruleIndex := map[string]int{}
+	for i, rule := range eval.Rules() {
+		ruleIndex[rule.ID()] = i
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:               rule.ID(),
+			ShortDescription: sarifMessage{Text: rule.Description()},
+			DefaultConfiguration: sarifConfiguration{
+				Level: sarifLevel(rule.Severity()),
+			},
+		})
+	}
+
+	for _, f := range r.Findings() {
+		if f.Passed {
+			continue
+		}
+		fqn := fmt.Sprintf("%s/%s/%s", f.Namespace, f.Kind, f.Workload)
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    f.RuleID,
+			RuleIndex: ruleIndex[f.RuleID],
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{
+							URI: fmt.Sprintf("%s/%s", r.ClusterName, fqn),
+						},
+					},
+					LogicalLocations: []sarifLogicalLocation{
+						{
+							Name:               f.Workload,
+							FullyQualifiedName: fqn,
+							Kind:               "object",
+						},
+					},
+				},
+			},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	})
+}
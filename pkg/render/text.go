@@ -0,0 +1,66 @@
+/*
+Copyright © 2021 Scott Hawkins <scott@echoboomer.net>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package render
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/echoboomer/paranoidaf/pkg/eval"
+	"github.com/kyokomi/emoji/v2"
+)
+
+// Text writes a Report to w as human-friendly, emoji-decorated text
+func Text(w io.Writer, r *eval.Report) error {
+	fmt.Fprintln(w)
+	for _, wl := range r.Workloads {
+		if _, err := emoji.Fprintf(w, ":package: %s (%s)\n", wl.Name, wl.Kind); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "----------------------------------------------------------------------\n")
+		if _, err := emoji.Fprintf(w, ":information_source:	Current replicas: %v\n", wl.Replicas); err != nil {
+			return err
+		}
+		if _, err := emoji.Fprintf(w, ":information_source:	Matching resources using labels: %v\n", wl.Labels); err != nil {
+			return err
+		}
+
+		for _, f := range wl.Findings {
+			if f.Passed {
+				if _, err := emoji.Fprintf(w, ":white_check_mark:	%s\n", f.Message); err != nil {
+					return err
+				}
+				continue
+			}
+			if _, err := emoji.Fprintf(w, ":warning:	%s\n", f.Message); err != nil {
+				return err
+			}
+			for _, s := range f.Suggestions {
+				if _, err := emoji.Fprintf(w, ":point_right:	Suggestion - %s\n", s); err != nil {
+					return err
+				}
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}